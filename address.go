@@ -0,0 +1,138 @@
+package mailfull
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors for the Address.
+var (
+	ErrAddressWithoutAt        = errors.New("Address: \"@\" not found")
+	ErrAddressLocalPartEmpty   = errors.New("Address: local part empty")
+	ErrInvalidAddressLocalPart = errors.New("Address: local part incorrect format")
+	ErrInvalidAddressQuote     = errors.New("Address: quoted local part incorrect format")
+	ErrInvalidAddressDomain    = errors.New("Address: domain incorrect format")
+)
+
+// addressSpecials is characters which must be quoted in a local part. (RFC 5322)
+const addressSpecials = "()<>[]:;@\\,\""
+
+// Address represents an email address.
+type Address struct {
+	localPart string
+	domain    string
+	quoted    bool
+}
+
+// ParseAddress parses the input as an email address.
+// A quoted local part is unquoted, and the domain is normalized by NormalizeDomainName.
+// e.g. "\"john\"@Example.COM" -> "john@example.com"
+// Addresses of existing entries written before the rules of names are also accepted.
+// Addresses set to new entries must be checked by validate.
+func ParseAddress(s string) (*Address, error) {
+	var localPart, domain string
+
+	quoted := strings.HasPrefix(s, `"`)
+	if quoted {
+		unquoted, rest, err := unquoteLocalPart(s)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(rest, "@") {
+			return nil, ErrInvalidAddressQuote
+		}
+
+		localPart, domain = unquoted, rest[1:]
+	} else {
+		i := strings.LastIndex(s, "@")
+		if i < 0 {
+			return nil, ErrAddressWithoutAt
+		}
+
+		localPart, domain = s[:i], s[i+1:]
+		if strings.ContainsAny(localPart, addressSpecials) || containsSpaceOrControl(localPart) {
+			return nil, &ValidationError{Err: ErrInvalidAddressLocalPart, Rule: ErrRuleLocalPartCharacter}
+		}
+	}
+
+	if localPart == "" {
+		return nil, ErrAddressLocalPartEmpty
+	}
+
+	domain = NormalizeDomainName(domain)
+	if err := validationError(ErrInvalidAddressDomain, existingDomainNameRule(domain)); err != nil {
+		return nil, err
+	}
+
+	return &Address{localPart: localPart, domain: domain, quoted: quoted}, nil
+}
+
+// validate returns an error if the address violates the rules for new entries.
+// A local part with misplaced dots is allowed only if it is quoted in the input.
+func (a *Address) validate() error {
+	if len(a.String())-len("@"+a.domain) > maxLocalPartLength {
+		return &ValidationError{Err: ErrInvalidAddressLocalPart, Rule: ErrRuleLocalPartTooLong}
+	}
+	if !a.quoted && !validDots(a.localPart) {
+		return &ValidationError{Err: ErrInvalidAddressLocalPart, Rule: ErrRuleLocalPartDot}
+	}
+
+	return validationError(ErrInvalidAddressDomain, domainNameRule(a.domain))
+}
+
+// unquoteLocalPart returns the unquoted content of the quoted string at the beginning of the input,
+// and the rest of the input.
+func unquoteLocalPart(s string) (string, string, error) {
+	buf := make([]byte, 0, len(s))
+
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			return string(buf), s[i+1:], nil
+		case c == '\\':
+			if i+1 >= len(s) {
+				return "", "", ErrInvalidAddressQuote
+			}
+			i++
+			buf = append(buf, s[i])
+		case c < 0x20 || c == 0x7f:
+			return "", "", ErrInvalidAddressQuote
+		default:
+			buf = append(buf, c)
+		}
+	}
+
+	return "", "", ErrInvalidAddressQuote
+}
+
+// containsSpaceOrControl returns true if the input contains spaces or control characters.
+func containsSpaceOrControl(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] <= 0x20 || s[i] == 0x7f {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LocalPart returns the unquoted local part.
+func (a *Address) LocalPart() string {
+	return a.localPart
+}
+
+// Domain returns the normalized domain name.
+func (a *Address) Domain() string {
+	return a.domain
+}
+
+// String returns the address. The local part is quoted if needed.
+func (a *Address) String() string {
+	localPart := a.localPart
+
+	if strings.ContainsAny(localPart, addressSpecials) || containsSpaceOrControl(localPart) || !validDots(localPart) {
+		localPart = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(localPart) + `"`
+	}
+
+	return localPart + "@" + a.domain
+}
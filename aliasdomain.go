@@ -17,6 +17,16 @@ type AliasDomain struct {
 
 // NewAliasDomain creates a new AliasDomain instance.
 func NewAliasDomain(name, target string) (*AliasDomain, error) {
+	if err := validationError(ErrInvalidAliasDomainName, domainNameRule(NormalizeDomainName(name))); err != nil {
+		return nil, err
+	}
+
+	return newAliasDomain(name, target)
+}
+
+// newAliasDomain creates a new AliasDomain instance of an existing AliasDomain.
+// The name written before the rules of domain names is accepted.
+func newAliasDomain(name, target string) (*AliasDomain, error) {
 	ad := &AliasDomain{}
 
 	if err := ad.setName(name); err != nil {
@@ -31,9 +41,11 @@ func NewAliasDomain(name, target string) (*AliasDomain, error) {
 }
 
 // setName sets the name.
+// The name is normalized by NormalizeDomainName.
 func (ad *AliasDomain) setName(name string) error {
-	if !validAliasDomainName(name) {
-		return ErrInvalidAliasDomainName
+	name = NormalizeDomainName(name)
+	if err := validationError(ErrInvalidAliasDomainName, existingDomainNameRule(name)); err != nil {
+		return err
 	}
 
 	ad.name = name
@@ -47,9 +59,11 @@ func (ad *AliasDomain) Name() string {
 }
 
 // SetTarget sets the target.
+// The target is normalized by NormalizeDomainName.
 func (ad *AliasDomain) SetTarget(target string) error {
-	if !validAliasDomainTarget(target) {
-		return ErrInvalidAliasDomainTarget
+	target = NormalizeDomainName(target)
+	if err := validationError(ErrInvalidAliasDomainTarget, existingDomainNameRule(target)); err != nil {
+		return err
 	}
 
 	ad.target = target
@@ -82,7 +96,7 @@ func (r *Repository) AliasDomains() ([]*AliasDomain, error) {
 		name := words[0]
 		target := words[1]
 
-		aliasDomain, err := NewAliasDomain(name, target)
+		aliasDomain, err := newAliasDomain(name, target)
 		if err != nil {
 			return nil, err
 		}
@@ -132,6 +146,9 @@ func (r *Repository) AliasDomainCreate(aliasDomain *AliasDomain) error {
 	if existDomain != nil {
 		return ErrDomainAlreadyExist
 	}
+	if err := r.checkDomainNameCollision(aliasDomain.Name()); err != nil {
+		return err
+	}
 	existDomain, err = r.Domain(aliasDomain.Target())
 	if err != nil {
 		return err
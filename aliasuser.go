@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"syscall"
 )
 
 // Errors for parameter.
@@ -15,14 +16,26 @@ var (
 	ErrNotEnoughAliasUserTargets = errors.New("AliasUser: targets not enough")
 )
 
+// Errors for the senders of the AliasUser.
+var (
+	ErrAliasUserSenderNotExist     = errors.New("AliasUser: sender not exist")
+	ErrAliasUserSenderAlreadyExist = errors.New("AliasUser: sender already exist")
+	ErrInvalidFormatAliasSenders   = errors.New("AliasUser: senders file invalid format")
+)
+
 // AliasUser represents a AliasUser.
 type AliasUser struct {
 	name    string
 	targets []string
+	senders []string
 }
 
 // NewAliasUser creates a new AliasUser instance.
 func NewAliasUser(name string, targets []string) (*AliasUser, error) {
+	if err := validationError(ErrInvalidAliasUserName, nameRule(name)); err != nil {
+		return nil, err
+	}
+
 	au := &AliasUser{}
 
 	if err := au.setName(name); err != nil {
@@ -36,10 +49,26 @@ func NewAliasUser(name string, targets []string) (*AliasUser, error) {
 	return au, nil
 }
 
+// newAliasUser creates a new AliasUser instance of an existing AliasUser.
+// The name and the targets written before the rules of names are accepted as is.
+func newAliasUser(name string, targets []string) (*AliasUser, error) {
+	au := &AliasUser{}
+
+	if err := au.setName(name); err != nil {
+		return nil, err
+	}
+
+	if err := au.setTargets(targets, false); err != nil {
+		return nil, err
+	}
+
+	return au, nil
+}
+
 // setName sets the name.
 func (au *AliasUser) setName(name string) error {
-	if !validAliasUserName(name) {
-		return ErrInvalidAliasUserName
+	if err := validationError(ErrInvalidAliasUserName, existingNameRule(name)); err != nil {
+		return err
 	}
 
 	au.name = name
@@ -53,18 +82,40 @@ func (au *AliasUser) Name() string {
 }
 
 // SetTargets sets targets.
+// Targets are normalized by ParseAddress.
 func (au *AliasUser) SetTargets(targets []string) error {
+	return au.setTargets(targets, true)
+}
+
+// setTargets sets targets.
+// If strict is false, the targets of an existing AliasUser are set as they are written.
+func (au *AliasUser) setTargets(targets []string, strict bool) error {
 	if len(targets) < 1 {
 		return ErrNotEnoughAliasUserTargets
 	}
 
-	for _, target := range targets {
-		if !validAliasUserTarget(target) {
-			return ErrInvalidAliasUserTarget
+	normalizedTargets := make([]string, len(targets))
+	for i, target := range targets {
+		address, err := ParseAddress(target)
+		if err != nil {
+			return err
+		}
+
+		if !strict {
+			normalizedTargets[i] = target
+			continue
+		}
+
+		if err := address.validate(); err != nil {
+			return err
+		}
+		normalizedTargets[i] = address.String()
+		if strings.ContainsAny(normalizedTargets[i], ":,") {
+			return &ValidationError{Err: ErrInvalidAliasUserTarget, Rule: ErrRuleSeparator}
 		}
 	}
 
-	au.targets = targets
+	au.targets = normalizedTargets
 
 	return nil
 }
@@ -74,6 +125,49 @@ func (au *AliasUser) Targets() []string {
 	return au.targets
 }
 
+// SetSenders sets users permitted to send as the AliasUser.
+// Senders are normalized by ParseAddress.
+func (au *AliasUser) SetSenders(senders []string) error {
+	return au.setSenders(senders, true)
+}
+
+// setSenders sets users permitted to send as the AliasUser.
+// If strict is false, the senders of an existing AliasUser are set as they are written.
+func (au *AliasUser) setSenders(senders []string, strict bool) error {
+	normalizedSenders := make([]string, len(senders))
+	for i, sender := range senders {
+		address, err := ParseAddress(sender)
+		if err != nil {
+			return err
+		}
+
+		if !strict {
+			if err := validationError(ErrInvalidAliasUserSender, existingNameRule(address.LocalPart())); err != nil {
+				return err
+			}
+			normalizedSenders[i] = sender
+			continue
+		}
+
+		if err := validationError(ErrInvalidAliasUserSender, nameRule(address.LocalPart())); err != nil {
+			return err
+		}
+		if err := address.validate(); err != nil {
+			return err
+		}
+		normalizedSenders[i] = address.String()
+	}
+
+	au.senders = normalizedSenders
+
+	return nil
+}
+
+// Senders returns users permitted to send as the AliasUser.
+func (au *AliasUser) Senders() []string {
+	return au.senders
+}
+
 // AliasUsers returns a AliasUser slice.
 func (r *Repository) AliasUsers(domainName string) ([]*AliasUser, error) {
 	domain, err := r.Domain(domainName)
@@ -102,7 +196,7 @@ func (r *Repository) AliasUsers(domainName string) ([]*AliasUser, error) {
 		name := words[0]
 		targets := strings.Split(words[1], ",")
 
-		aliasUser, err := NewAliasUser(name, targets)
+		aliasUser, err := newAliasUser(name, targets)
 		if err != nil {
 			return nil, err
 		}
@@ -114,9 +208,49 @@ func (r *Repository) AliasUsers(domainName string) ([]*AliasUser, error) {
 		return nil, err
 	}
 
+	if err := r.readAliasSendersFile(domainName, aliasUsers); err != nil {
+		return nil, err
+	}
+
 	return aliasUsers, nil
 }
 
+// readAliasSendersFile sets senders read from the file to the AliasUsers.
+func (r *Repository) readAliasSendersFile(domainName string, aliasUsers []*AliasUser) error {
+	file, err := os.Open(filepath.Join(r.DirMailDataPath, domainName, FileNameAliasSenders))
+	if err != nil {
+		if err.(*os.PathError).Err == syscall.ENOENT {
+			return nil
+		}
+
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		words := strings.Split(scanner.Text(), ":")
+		if len(words) != 2 {
+			return ErrInvalidFormatAliasSenders
+		}
+
+		name := words[0]
+		senders := strings.Split(words[1], ",")
+
+		for _, aliasUser := range aliasUsers {
+			if aliasUser.Name() != name {
+				continue
+			}
+
+			if err := aliasUser.setSenders(senders, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
 // AliasUser returns a AliasUser of the input name.
 func (r *Repository) AliasUser(domainName, aliasUserName string) (*AliasUser, error) {
 	aliasUsers, err := r.AliasUsers(domainName)
@@ -152,6 +286,9 @@ func (r *Repository) AliasUserCreate(domainName string, aliasUser *AliasUser) er
 	if existUser != nil {
 		return ErrUserAlreadyExist
 	}
+	if err := r.checkNameCollision(domainName, aliasUser.Name()); err != nil {
+		return err
+	}
 
 	aliasUsers = append(aliasUsers, aliasUser)
 
@@ -214,6 +351,83 @@ func (r *Repository) AliasUserRemove(domainName string, aliasUserName string) er
 	return nil
 }
 
+// AliasUserSenderAdd permits the User of the input address to send as the AliasUser.
+func (r *Repository) AliasUserSenderAdd(domainName, aliasUserName, sender string) error {
+	address, err := ParseAddress(sender)
+	if err != nil {
+		return err
+	}
+	sender = address.String()
+
+	aliasUser, err := r.AliasUser(domainName, aliasUserName)
+	if err != nil {
+		return err
+	}
+	if aliasUser == nil {
+		return ErrAliasUserNotExist
+	}
+
+	if err := validationError(ErrInvalidAliasUserSender, nameRule(address.LocalPart())); err != nil {
+		return err
+	}
+	if err := address.validate(); err != nil {
+		return err
+	}
+	existUser, err := r.User(address.Domain(), address.LocalPart())
+	if err != nil {
+		return err
+	}
+	if existUser == nil {
+		return ErrUserNotExist
+	}
+
+	for _, s := range aliasUser.Senders() {
+		if strings.EqualFold(s, sender) {
+			return ErrAliasUserSenderAlreadyExist
+		}
+	}
+
+	// the existing senders are kept as they are written
+	if err := aliasUser.setSenders(append(aliasUser.Senders(), sender), false); err != nil {
+		return err
+	}
+
+	return r.AliasUserUpdate(domainName, aliasUser)
+}
+
+// AliasUserSenderRemove revokes the permission of the input address to send as the AliasUser.
+func (r *Repository) AliasUserSenderRemove(domainName, aliasUserName, sender string) error {
+	address, err := ParseAddress(sender)
+	if err != nil {
+		return err
+	}
+	sender = address.String()
+
+	aliasUser, err := r.AliasUser(domainName, aliasUserName)
+	if err != nil {
+		return err
+	}
+	if aliasUser == nil {
+		return ErrAliasUserNotExist
+	}
+
+	senders := []string{}
+	for _, s := range aliasUser.Senders() {
+		if !strings.EqualFold(s, sender) {
+			senders = append(senders, s)
+		}
+	}
+	if len(senders) == len(aliasUser.Senders()) {
+		return ErrAliasUserSenderNotExist
+	}
+
+	if err := aliasUser.setSenders(senders, false); err != nil {
+		return err
+	}
+
+	return r.AliasUserUpdate(domainName, aliasUser)
+}
+
 // writeAliasUsersFile writes a AliasUser slice to the file.
 func (r *Repository) writeAliasUsersFile(domainName string, aliasUsers []*AliasUser) error {
 	if !validDomainName(domainName) {
@@ -234,5 +448,29 @@ func (r *Repository) writeAliasUsersFile(domainName string, aliasUsers []*AliasU
 		}
 	}
 
+	return r.writeAliasSendersFile(domainName, aliasUsers)
+}
+
+// writeAliasSendersFile writes senders of a AliasUser slice to the file.
+func (r *Repository) writeAliasSendersFile(domainName string, aliasUsers []*AliasUser) error {
+	file, err := os.OpenFile(filepath.Join(r.DirMailDataPath, domainName, FileNameAliasSenders), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := file.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for _, aliasUser := range aliasUsers {
+		if len(aliasUser.Senders()) == 0 {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(file, "%s:%s\n", aliasUser.Name(), strings.Join(aliasUser.Senders(), ",")); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// aliasDomainJSON represents a AliasDomain in JSON.
+type aliasDomainJSON struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+}
+
+// newAliasDomainJSON creates a new aliasDomainJSON instance.
+func newAliasDomainJSON(aliasDomain *mailfull.AliasDomain) *aliasDomainJSON {
+	return &aliasDomainJSON{
+		Name:   aliasDomain.Name(),
+		Target: aliasDomain.Target(),
+	}
+}
+
+// handleAliasDomains handles "/aliasdomains".
+func (s *Server) handleAliasDomains(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		aliasDomains, err := s.repo.AliasDomains()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		sort.Slice(aliasDomains, func(i, j int) bool { return aliasDomains[i].Name() < aliasDomains[j].Name() })
+
+		res := make([]*aliasDomainJSON, 0, len(aliasDomains))
+		for _, aliasDomain := range aliasDomains {
+			res = append(res, newAliasDomainJSON(aliasDomain))
+		}
+
+		writeJSON(w, http.StatusOK, res)
+
+	case http.MethodPost:
+		body := &aliasDomainJSON{}
+		if err := readJSON(req, body); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		aliasDomain, err := mailfull.NewAliasDomain(body.Name, body.Target)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.repo.AliasDomainCreate(aliasDomain); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, newAliasDomainJSON(aliasDomain))
+
+	default:
+		writeError(w, ErrMethodNotAllowed)
+	}
+}
+
+// handleAliasDomain handles "/aliasdomains/{aliasdomain}".
+func (s *Server) handleAliasDomain(w http.ResponseWriter, req *http.Request, aliasDomainName string) {
+	switch req.Method {
+	case http.MethodGet:
+		aliasDomain, err := s.repo.AliasDomain(aliasDomainName)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if aliasDomain == nil {
+			writeError(w, mailfull.ErrAliasDomainNotExist)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newAliasDomainJSON(aliasDomain))
+
+	case http.MethodDelete:
+		if err := s.repo.AliasDomainRemove(aliasDomainName); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, ErrMethodNotAllowed)
+	}
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// aliasUserJSON represents a AliasUser in JSON.
+type aliasUserJSON struct {
+	Name    string   `json:"name"`
+	Targets []string `json:"targets"`
+	Senders []string `json:"senders,omitempty"`
+}
+
+// newAliasUserJSON creates a new aliasUserJSON instance.
+func newAliasUserJSON(aliasUser *mailfull.AliasUser) *aliasUserJSON {
+	return &aliasUserJSON{
+		Name:    aliasUser.Name(),
+		Targets: aliasUser.Targets(),
+		Senders: aliasUser.Senders(),
+	}
+}
+
+// aliasUserUpdateJSON represents a request body to update a AliasUser.
+type aliasUserUpdateJSON struct {
+	Targets []string `json:"targets"`
+}
+
+// handleAliasUsers handles "/domains/{domain}/aliasusers".
+func (s *Server) handleAliasUsers(w http.ResponseWriter, req *http.Request, domainName string) {
+	switch req.Method {
+	case http.MethodGet:
+		aliasUsers, err := s.repo.AliasUsers(domainName)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		sort.Slice(aliasUsers, func(i, j int) bool { return aliasUsers[i].Name() < aliasUsers[j].Name() })
+
+		res := make([]*aliasUserJSON, 0, len(aliasUsers))
+		for _, aliasUser := range aliasUsers {
+			res = append(res, newAliasUserJSON(aliasUser))
+		}
+
+		writeJSON(w, http.StatusOK, res)
+
+	case http.MethodPost:
+		body := &aliasUserJSON{}
+		if err := readJSON(req, body); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		aliasUser, err := mailfull.NewAliasUser(body.Name, body.Targets)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.repo.AliasUserCreate(domainName, aliasUser); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, newAliasUserJSON(aliasUser))
+
+	default:
+		writeError(w, ErrMethodNotAllowed)
+	}
+}
+
+// handleAliasUser handles "/domains/{domain}/aliasusers/{aliasuser}".
+func (s *Server) handleAliasUser(w http.ResponseWriter, req *http.Request, domainName, aliasUserName string) {
+	switch req.Method {
+	case http.MethodGet:
+		aliasUser, err := s.repo.AliasUser(domainName, aliasUserName)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if aliasUser == nil {
+			writeError(w, mailfull.ErrAliasUserNotExist)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newAliasUserJSON(aliasUser))
+
+	case http.MethodPut:
+		body := &aliasUserUpdateJSON{}
+		if err := readJSON(req, body); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		aliasUser, err := s.repo.AliasUser(domainName, aliasUserName)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if aliasUser == nil {
+			writeError(w, mailfull.ErrAliasUserNotExist)
+			return
+		}
+
+		if err := aliasUser.SetTargets(body.Targets); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.repo.AliasUserUpdate(domainName, aliasUser); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newAliasUserJSON(aliasUser))
+
+	case http.MethodDelete:
+		if err := s.repo.AliasUserRemove(domainName, aliasUserName); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, ErrMethodNotAllowed)
+	}
+}
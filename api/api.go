@@ -0,0 +1,270 @@
+/*
+Package api provides a JSON REST API for a mailfull repository.
+*/
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// Errors for the API.
+var (
+	ErrNotFound         = errors.New("API: not found")
+	ErrMethodNotAllowed = errors.New("API: method not allowed")
+	ErrInvalidBody      = errors.New("API: request body invalid format")
+	ErrInvalidPath      = errors.New("API: path invalid format")
+)
+
+// Server represents a Server.
+type Server struct {
+	repo *mailfull.Repository
+
+	// mu serializes changes to the Repository.
+	mu sync.RWMutex
+
+	failures *failureLimiter
+}
+
+// NewServer creates a new Server instance.
+func NewServer(repo *mailfull.Repository) *Server {
+	s := &Server{
+		repo: repo,
+
+		failures: newFailureLimiter(),
+	}
+
+	return s
+}
+
+// ServeHTTP authorizes the request and dispatches it to the handler of the resource.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	paths, err := splitPath(req.URL.Path)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// a domain name is referred in the normalized form
+	if len(paths) >= 2 && (paths[0] == "domains" || paths[0] == "aliasdomains") {
+		paths[1] = mailfull.NormalizeDomainName(paths[1])
+	}
+
+	if isPasswordChange(paths) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.handlePasswordChange(w, req, paths[1], paths[3])
+		return
+	}
+
+	apiToken, err := s.authenticate(req)
+	if err != nil {
+		if err == ErrUnauthorized {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mailfull"`)
+		}
+		writeError(w, err)
+		return
+	}
+	if err := authorize(apiToken, req, paths); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	} else {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+
+	switch {
+	case len(paths) == 1 && paths[0] == "domains":
+		s.handleDomains(w, req)
+	case len(paths) == 2 && paths[0] == "domains":
+		s.handleDomain(w, req, paths[1])
+	case len(paths) == 3 && paths[0] == "domains" && paths[2] == "users":
+		s.handleUsers(w, req, paths[1])
+	case len(paths) == 4 && paths[0] == "domains" && paths[2] == "users":
+		s.handleUser(w, req, paths[1], paths[3])
+	case len(paths) == 3 && paths[0] == "domains" && paths[2] == "aliasusers":
+		s.handleAliasUsers(w, req, paths[1])
+	case len(paths) == 4 && paths[0] == "domains" && paths[2] == "aliasusers":
+		s.handleAliasUser(w, req, paths[1], paths[3])
+	case len(paths) == 3 && paths[0] == "domains" && paths[2] == "catchall":
+		s.handleCatchAllUser(w, req, paths[1])
+	case len(paths) == 1 && paths[0] == "aliasdomains":
+		s.handleAliasDomains(w, req)
+	case len(paths) == 2 && paths[0] == "aliasdomains":
+		s.handleAliasDomain(w, req, paths[1])
+	case len(paths) == 1 && paths[0] == "commit":
+		s.handleCommit(w, req)
+	default:
+		writeError(w, ErrNotFound)
+	}
+}
+
+// handleCommit handles "/commit".
+func (s *Server) handleCommit(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, ErrMethodNotAllowed)
+		return
+	}
+
+	if err := s.repo.GenerateDatabases(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// splitPath splits the URL path into non-empty segments.
+// A path with "." or ".." segments is rejected so that every segment is a name as is.
+func splitPath(urlPath string) ([]string, error) {
+	paths := make([]string, 0, 4)
+
+	for _, p := range strings.Split(path.Clean("/"+urlPath), "/") {
+		if p == "" {
+			continue
+		}
+		paths = append(paths, p)
+	}
+
+	for _, p := range strings.Split(urlPath, "/") {
+		if p == "." || p == ".." {
+			return nil, ErrInvalidPath
+		}
+	}
+
+	return paths, nil
+}
+
+// readJSON decodes the request body into v.
+func readJSON(req *http.Request, v interface{}) error {
+	if err := json.NewDecoder(req.Body).Decode(v); err != nil {
+		return ErrInvalidBody
+	}
+
+	return nil
+}
+
+// writeJSON writes v as a JSON response with the status code.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// formatExpiry returns the expiry in RFC 3339, or "" if the expiry is not set.
+func formatExpiry(expiresAt time.Time) string {
+	if expiresAt.IsZero() {
+		return ""
+	}
+
+	return expiresAt.Format(time.RFC3339)
+}
+
+// parseExpiry parses the expiry in a request body. "" removes the expiry.
+func parseExpiry(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	return mailfull.ParseExpiry(s)
+}
+
+// setMetadata sets the values in a request body to the Metadata. An empty value removes the key.
+func setMetadata(metadata mailfull.Metadata, values map[string]string) error {
+	for key, value := range values {
+		if err := metadata.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// errorResponse represents an error response.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError writes the error as a JSON response with the status code mapped from the error.
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, errorStatus(err), &errorResponse{Error: err.Error()})
+}
+
+// errorStatus returns a HTTP status code for the error.
+func errorStatus(err error) int {
+	if _, ok := err.(*mailfull.ValidationError); ok {
+		return http.StatusBadRequest
+	}
+
+	switch err {
+	case ErrNotFound,
+		mailfull.ErrDomainNotExist,
+		mailfull.ErrAliasDomainNotExist,
+		mailfull.ErrUserNotExist,
+		mailfull.ErrAliasUserNotExist,
+		mailfull.ErrAliasUserSenderNotExist:
+		return http.StatusNotFound
+
+	case ErrUnauthorized:
+		return http.StatusUnauthorized
+
+	case ErrForbidden,
+		mailfull.ErrPasswordMismatch:
+		return http.StatusForbidden
+
+	case ErrTooManyFailures:
+		return http.StatusTooManyRequests
+
+	case ErrMethodNotAllowed:
+		return http.StatusMethodNotAllowed
+
+	case mailfull.ErrDomainAlreadyExist,
+		mailfull.ErrAliasDomainAlreadyExist,
+		mailfull.ErrUserAlreadyExist,
+		mailfull.ErrAliasUserAlreadyExist,
+		mailfull.ErrAliasUserSenderAlreadyExist,
+		mailfull.ErrDomainIsAliasDomainTarget,
+		mailfull.ErrUserIsCatchAllUser:
+		return http.StatusConflict
+
+	case ErrInvalidBody,
+		ErrInvalidPath,
+		mailfull.ErrPasswordTooShort,
+		mailfull.ErrPasswordUnchanged,
+		mailfull.ErrInvalidDomainName,
+		mailfull.ErrInvalidAliasDomainName,
+		mailfull.ErrInvalidAliasDomainTarget,
+		mailfull.ErrInvalidUserName,
+		mailfull.ErrInvalidAliasUserName,
+		mailfull.ErrInvalidAliasUserTarget,
+		mailfull.ErrInvalidAliasUserSender,
+		mailfull.ErrAddressWithoutAt,
+		mailfull.ErrAddressLocalPartEmpty,
+		mailfull.ErrInvalidAddressLocalPart,
+		mailfull.ErrInvalidAddressQuote,
+		mailfull.ErrInvalidAddressDomain,
+		mailfull.ErrInvalidCatchAllUserName,
+		mailfull.ErrInvalidExpiry,
+		mailfull.ErrInvalidUserAllowNet,
+		mailfull.ErrInvalidUserProtocol,
+		mailfull.ErrInvalidMetadataKey,
+		mailfull.ErrInvalidMetadataValue,
+		mailfull.ErrNotEnoughAliasUserTargets:
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
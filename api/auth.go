@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// Errors for the authentication.
+var (
+	ErrUnauthorized = errors.New("API: token required or invalid")
+	ErrForbidden    = errors.New("API: out of the token scope")
+)
+
+// authenticate returns a APIToken of the Bearer token in the request.
+func (s *Server) authenticate(req *http.Request) (*mailfull.APIToken, error) {
+	header := req.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, ErrUnauthorized
+	}
+
+	rawToken := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	if rawToken == "" {
+		return nil, ErrUnauthorized
+	}
+
+	apiToken, err := s.repo.APITokenAuthenticate(rawToken)
+	if err != nil {
+		return nil, err
+	}
+	if apiToken == nil {
+		return nil, ErrUnauthorized
+	}
+
+	return apiToken, nil
+}
+
+// authorize returns an error if the request is out of the APIToken scope.
+//
+// A domain-scoped APIToken can only refer to its own Domain,
+// manage users, aliasusers and the catchall user inside the Domain, and commit.
+func authorize(apiToken *mailfull.APIToken, req *http.Request, paths []string) error {
+	if apiToken.IsAdmin() {
+		return nil
+	}
+
+	switch {
+	case len(paths) == 1 && paths[0] == "commit":
+		return nil
+
+	case len(paths) == 2 && paths[0] == "domains" && paths[1] == apiToken.Scope():
+		if req.Method == http.MethodGet || req.Method == http.MethodHead {
+			return nil
+		}
+
+	case len(paths) >= 3 && paths[0] == "domains" && paths[1] == apiToken.Scope():
+		switch paths[2] {
+		case "users", "aliasusers", "catchall":
+			return nil
+		}
+	}
+
+	return ErrForbidden
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// newTestServer creates a Server of a new Repository which has the Domains.
+// The returned func removes the Repository.
+func newTestServer(t *testing.T, domainNames ...string) (*Server, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "mailfull-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	if err := mailfull.InitRepository(dir); err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	repo, err := mailfull.OpenRepository(dir)
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+
+	for _, domainName := range domainNames {
+		domain, err := mailfull.NewDomain(domainName)
+		if err != nil {
+			cleanup()
+			t.Fatal(err)
+		}
+		if err := repo.DomainCreate(domain); err != nil {
+			cleanup()
+			t.Fatal(err)
+		}
+	}
+
+	return NewServer(repo), cleanup
+}
+
+// createTestAPIToken creates an APIToken of the scope and returns the raw token.
+func createTestAPIToken(t *testing.T, s *Server, name, scope string) string {
+	t.Helper()
+
+	apiToken, rawToken, err := mailfull.GenerateAPIToken(name, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.repo.APITokenCreate(apiToken); err != nil {
+		t.Fatal(err)
+	}
+
+	return rawToken
+}
+
+func TestAuthorize(t *testing.T) {
+	adminToken, err := mailfull.NewAPIToken("admin", mailfull.APITokenScopeAdmin, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	domainToken, err := mailfull.NewAPIToken("example", "example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		apiToken *mailfull.APIToken
+		method   string
+		path     string
+		allowed  bool
+	}{
+		{adminToken, http.MethodGet, "/domains", true},
+		{adminToken, http.MethodPost, "/domains", true},
+		{adminToken, http.MethodDelete, "/domains/other.com", true},
+		{adminToken, http.MethodPost, "/aliasdomains", true},
+
+		{domainToken, http.MethodPost, "/commit", true},
+		{domainToken, http.MethodGet, "/domains/example.com", true},
+		{domainToken, http.MethodHead, "/domains/example.com", true},
+		{domainToken, http.MethodGet, "/domains/example.com/users", true},
+		{domainToken, http.MethodPost, "/domains/example.com/users", true},
+		{domainToken, http.MethodDelete, "/domains/example.com/users/bob", true},
+		{domainToken, http.MethodPut, "/domains/example.com/aliasusers/info", true},
+		{domainToken, http.MethodPut, "/domains/example.com/catchall", true},
+
+		{domainToken, http.MethodGet, "/domains", false},
+		{domainToken, http.MethodPost, "/domains", false},
+		{domainToken, http.MethodDelete, "/domains/example.com", false},
+		{domainToken, http.MethodPut, "/domains/example.com", false},
+		{domainToken, http.MethodGet, "/domains/other.com", false},
+		{domainToken, http.MethodGet, "/domains/other.com/users", false},
+		{domainToken, http.MethodPost, "/domains/other.com/users", false},
+		{domainToken, http.MethodGet, "/domains/example.com.other.com/users", false},
+		{domainToken, http.MethodGet, "/aliasdomains", false},
+		{domainToken, http.MethodGet, "/domains/example.com/unknown", false},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		paths, err := splitPath(req.URL.Path)
+		if err != nil {
+			t.Fatalf("splitPath(%q): %v", test.path, err)
+		}
+
+		err = authorize(test.apiToken, req, paths)
+		if test.allowed && err != nil {
+			t.Errorf("%s %s by %s: got %v, want allowed", test.method, test.path, test.apiToken.Scope(), err)
+		}
+		if !test.allowed && err != ErrForbidden {
+			t.Errorf("%s %s by %s: got %v, want %v", test.method, test.path, test.apiToken.Scope(), err, ErrForbidden)
+		}
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		paths []string
+		err   error
+	}{
+		{"/domains", []string{"domains"}, nil},
+		{"/domains/", []string{"domains"}, nil},
+		{"//domains//example.com/users", []string{"domains", "example.com", "users"}, nil},
+		{"/domains/example.com/users/x/../../other.com/bob", nil, ErrInvalidPath},
+		{"/domains/example.com/users/..", nil, ErrInvalidPath},
+		{"/domains/example.com/./users", nil, ErrInvalidPath},
+		{"/../domains", nil, ErrInvalidPath},
+	}
+
+	for _, test := range tests {
+		paths, err := splitPath(test.path)
+		if err != test.err {
+			t.Errorf("splitPath(%q): got error %v, want %v", test.path, err, test.err)
+			continue
+		}
+		if strings.Join(paths, "/") != strings.Join(test.paths, "/") {
+			t.Errorf("splitPath(%q): got %q, want %q", test.path, paths, test.paths)
+		}
+	}
+}
+
+func TestServeHTTPPathEscape(t *testing.T) {
+	s, cleanup := newTestServer(t, "example.com", "other.com")
+	defer cleanup()
+
+	rawToken := createTestAPIToken(t, s, "example", "example.com")
+
+	paths := []string{
+		"/domains/example.com/users/x/../../other.com/users/bob",
+		"/domains/example.com/users/x%2F..%2F..%2Fother.com%2Fusers%2Fbob",
+		"/domains/example.com/users/..%2F..%2Fother.com%2Fusers%2Fbob",
+		"/domains/example.com/aliasusers/x%2f..%2f..%2fother.com%2fusers%2fbob",
+	}
+
+	for _, path := range paths {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"name":"bob","password":"password1234"}`))
+		req.Header.Set("Authorization", "Bearer "+rawToken)
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("POST %s: got status %d, want %d", path, w.Code, http.StatusBadRequest)
+		}
+	}
+
+	users, err := s.repo.Users("other.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 0 {
+		t.Errorf("other.com has %d users, want 0", len(users))
+	}
+}
+
+func TestServeHTTPScope(t *testing.T) {
+	s, cleanup := newTestServer(t, "example.com", "other.com")
+	defer cleanup()
+
+	rawToken := createTestAPIToken(t, s, "example", "example.com")
+
+	tests := []struct {
+		method string
+		path   string
+		token  string
+		code   int
+	}{
+		{http.MethodGet, "/domains/example.com/users", "", http.StatusUnauthorized},
+		{http.MethodGet, "/domains/example.com/users", "invalid", http.StatusUnauthorized},
+		{http.MethodGet, "/domains/example.com/users", rawToken, http.StatusOK},
+		{http.MethodGet, "/domains/EXAMPLE.COM/users", rawToken, http.StatusOK},
+		{http.MethodGet, "/domains/other.com/users", rawToken, http.StatusForbidden},
+		{http.MethodGet, "/domains", rawToken, http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		if test.token != "" {
+			req.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		if w.Code != test.code {
+			t.Errorf("%s %s: got status %d, want %d", test.method, test.path, w.Code, test.code)
+		}
+	}
+}
+
+func TestDomainRemoveRevokesAPITokens(t *testing.T) {
+	s, cleanup := newTestServer(t, "example.com")
+	defer cleanup()
+
+	rawToken := createTestAPIToken(t, s, "example", "example.com")
+
+	if err := s.repo.DomainRemove("example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	domain, err := mailfull.NewDomain("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.repo.DomainCreate(domain); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/domains/example.com/users", nil)
+	req.Header.Set("Authorization", "Bearer "+rawToken)
+	w := httptest.NewRecorder()
+
+	s.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
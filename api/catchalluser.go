@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// catchAllUserJSON represents a CatchAllUser in JSON.
+// Name is empty if the Domain has no CatchAllUser.
+type catchAllUserJSON struct {
+	Name string `json:"name"`
+}
+
+// handleCatchAllUser handles "/domains/{domain}/catchall".
+func (s *Server) handleCatchAllUser(w http.ResponseWriter, req *http.Request, domainName string) {
+	switch req.Method {
+	case http.MethodGet:
+		catchAllUser, err := s.repo.CatchAllUser(domainName)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		res := &catchAllUserJSON{}
+		if catchAllUser != nil {
+			res.Name = catchAllUser.Name()
+		}
+
+		writeJSON(w, http.StatusOK, res)
+
+	case http.MethodPut:
+		body := &catchAllUserJSON{}
+		if err := readJSON(req, body); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		catchAllUser, err := mailfull.NewCatchAllUser(body.Name)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.repo.CatchAllUserSet(domainName, catchAllUser); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, body)
+
+	case http.MethodDelete:
+		if err := s.repo.CatchAllUserUnset(domainName); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, ErrMethodNotAllowed)
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// domainJSON represents a Domain in JSON.
+type domainJSON struct {
+	Name      string            `json:"name"`
+	Disabled  bool              `json:"disabled"`
+	ExpiresAt string            `json:"expires_at,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// newDomainJSON creates a new domainJSON instance.
+func newDomainJSON(domain *mailfull.Domain) *domainJSON {
+	return &domainJSON{
+		Name:      domain.Name(),
+		Disabled:  domain.Disabled(),
+		ExpiresAt: formatExpiry(domain.ExpiresAt()),
+		Metadata:  domain.Metadata(),
+	}
+}
+
+// domainUpdateJSON represents a request body to update a Domain.
+type domainUpdateJSON struct {
+	Disabled  *bool             `json:"disabled"`
+	ExpiresAt *string           `json:"expires_at"`
+	Metadata  map[string]string `json:"metadata"`
+}
+
+// handleDomains handles "/domains".
+func (s *Server) handleDomains(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		domains, err := s.repo.Domains()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		sort.Slice(domains, func(i, j int) bool { return domains[i].Name() < domains[j].Name() })
+
+		res := make([]*domainJSON, 0, len(domains))
+		for _, domain := range domains {
+			res = append(res, newDomainJSON(domain))
+		}
+
+		writeJSON(w, http.StatusOK, res)
+
+	case http.MethodPost:
+		body := &domainJSON{}
+		if err := readJSON(req, body); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		domain, err := mailfull.NewDomain(body.Name)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		domain.SetDisabled(body.Disabled)
+
+		if err := s.repo.DomainCreate(domain); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		user, err := mailfull.NewUser("postmaster", mailfull.NeverMatchHashedPassword, nil)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.repo.UserCreate(domain.Name(), user); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, newDomainJSON(domain))
+
+	default:
+		writeError(w, ErrMethodNotAllowed)
+	}
+}
+
+// handleDomain handles "/domains/{domain}".
+func (s *Server) handleDomain(w http.ResponseWriter, req *http.Request, domainName string) {
+	switch req.Method {
+	case http.MethodGet:
+		domain, err := s.repo.Domain(domainName)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if domain == nil {
+			writeError(w, mailfull.ErrDomainNotExist)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newDomainJSON(domain))
+
+	case http.MethodPut:
+		body := &domainUpdateJSON{}
+		if err := readJSON(req, body); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		domain, err := s.repo.Domain(domainName)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if domain == nil {
+			writeError(w, mailfull.ErrDomainNotExist)
+			return
+		}
+
+		if body.Disabled != nil {
+			domain.SetDisabled(*body.Disabled)
+		}
+		if body.ExpiresAt != nil {
+			expiresAt, err := parseExpiry(*body.ExpiresAt)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			domain.SetExpiresAt(expiresAt)
+		}
+		if err := setMetadata(domain.Metadata(), body.Metadata); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.repo.DomainUpdate(domain); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newDomainJSON(domain))
+
+	case http.MethodDelete:
+		if err := s.repo.DomainRemove(domainName); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, ErrMethodNotAllowed)
+	}
+}
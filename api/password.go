@@ -0,0 +1,185 @@
+package api
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// Errors for the password change.
+var (
+	ErrTooManyFailures = errors.New("API: too many failed attempts, try again later")
+)
+
+// Parameters of the failure limiter.
+const (
+	maxFailures    = 5
+	failureWindow  = 15 * time.Minute
+	maxFailureKeys = 10000
+)
+
+// passwordChangeJSON represents a request body to change the password by the User.
+type passwordChangeJSON struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// isPasswordChange returns true if the paths is "/domains/{domain}/users/{user}/password".
+func isPasswordChange(paths []string) bool {
+	return len(paths) == 5 && paths[0] == "domains" && paths[2] == "users" && paths[4] == "password"
+}
+
+// handlePasswordChange handles "/domains/{domain}/users/{user}/password".
+// The request is authenticated by the current password instead of the APIToken.
+func (s *Server) handlePasswordChange(w http.ResponseWriter, req *http.Request, domainName, userName string) {
+	if req.Method != http.MethodPost {
+		writeError(w, ErrMethodNotAllowed)
+		return
+	}
+
+	body := &passwordChangeJSON{}
+	if err := readJSON(req, body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	remoteHost, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		remoteHost = req.RemoteAddr
+	}
+	keys := []string{failureUserKey(domainName, userName), "host:" + remoteHost}
+
+	if s.failures.exceeded(keys...) {
+		writeError(w, ErrTooManyFailures)
+		return
+	}
+
+	if err := s.repo.UserChangePassword(domainName, userName, body.CurrentPassword, body.NewPassword); err != nil {
+		if err == mailfull.ErrPasswordMismatch {
+			s.failures.add(keys...)
+		}
+		writeError(w, err)
+		return
+	}
+
+	s.failures.reset(keys[0])
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// failureUserKey returns the key of the failure limiter for the User.
+// The names are normalized so that another form of the same address shares the count.
+func failureUserKey(domainName, userName string) string {
+	return "user:" + strings.ToLower(userName) + "@" + mailfull.NormalizeDomainName(domainName)
+}
+
+// failureLimiter counts failed attempts per key within the window.
+// Keys are limited to maxFailureKeys, and expired keys are swept on insert.
+type failureLimiter struct {
+	mu        sync.Mutex
+	failures  map[string][]time.Time
+	lastSweep time.Time
+}
+
+// newFailureLimiter creates a new failureLimiter instance.
+func newFailureLimiter() *failureLimiter {
+	l := &failureLimiter{
+		failures: map[string][]time.Time{},
+	}
+
+	return l
+}
+
+// exceeded returns true if any of the keys reaches the limit.
+func (l *failureLimiter) exceeded(keys ...string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if len(l.expire(key)) >= maxFailures {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add records a failed attempt for each of the keys.
+func (l *failureLimiter) add(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) >= failureWindow || len(l.failures) >= maxFailureKeys {
+		l.sweep()
+		l.lastSweep = now
+	}
+
+	for _, key := range keys {
+		failures := l.expire(key)
+		if failures == nil && len(l.failures) >= maxFailureKeys {
+			l.evictOldest()
+		}
+		l.failures[key] = append(failures, now)
+	}
+}
+
+// reset forgets failed attempts for each of the keys.
+func (l *failureLimiter) reset(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		delete(l.failures, key)
+	}
+}
+
+// sweep drops failed attempts out of the window of all keys.
+// l.mu must be held.
+func (l *failureLimiter) sweep() {
+	for key := range l.failures {
+		l.expire(key)
+	}
+}
+
+// evictOldest drops the key whose last failed attempt is the oldest.
+// l.mu must be held.
+func (l *failureLimiter) evictOldest() {
+	oldestKey := ""
+	var oldest time.Time
+
+	for key, failures := range l.failures {
+		last := failures[len(failures)-1]
+		if oldestKey == "" || last.Before(oldest) {
+			oldestKey, oldest = key, last
+		}
+	}
+
+	delete(l.failures, oldestKey)
+}
+
+// expire drops failed attempts out of the window and returns the rest.
+// l.mu must be held.
+func (l *failureLimiter) expire(key string) []time.Time {
+	since := time.Now().Add(-failureWindow)
+
+	failures := l.failures[key]
+	idx := 0
+	for idx < len(failures) && failures[idx].Before(since) {
+		idx++
+	}
+	failures = failures[idx:]
+
+	if len(failures) == 0 {
+		delete(l.failures, key)
+		return nil
+	}
+	l.failures[key] = failures
+
+	return failures
+}
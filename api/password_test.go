@@ -0,0 +1,195 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// createTestUser creates a User with the raw password.
+func createTestUser(t *testing.T, s *Server, domainName, userName, rawPassword string) {
+	t.Helper()
+
+	hashedPassword, err := mailfull.HashPassword(rawPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := mailfull.NewUser(userName, hashedPassword, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.repo.UserCreate(domainName, user); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// changePassword requests the password change and returns the status code.
+func changePassword(s *Server, remoteAddr, address, currentPassword, newPassword string) int {
+	words := strings.SplitN(address, "@", 2)
+	body := `{"current_password":"` + currentPassword + `","new_password":"` + newPassword + `"}`
+
+	req := httptest.NewRequest(http.MethodPost, "/domains/"+words[1]+"/users/"+words[0]+"/password", strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+
+	s.ServeHTTP(w, req)
+
+	return w.Code
+}
+
+func TestPasswordChange(t *testing.T) {
+	s, cleanup := newTestServer(t, "example.com")
+	defer cleanup()
+
+	createTestUser(t, s, "example.com", "bob", "password1234")
+
+	tests := []struct {
+		address         string
+		currentPassword string
+		newPassword     string
+		code            int
+	}{
+		{"bob@example.com", "wrong", "password5678", http.StatusForbidden},
+		{"nobody@example.com", "password1234", "password5678", http.StatusForbidden},
+		{"bob@other.com", "password1234", "password5678", http.StatusForbidden},
+		{"bob@example.com", "password1234", "short", http.StatusBadRequest},
+		{"bob@example.com", "password1234", "password1234", http.StatusBadRequest},
+		{"bob@example.com", "password1234", "password5678", http.StatusNoContent},
+		{"bob@example.com", "password1234", "password9012", http.StatusForbidden},
+		{"bob@example.com", "password5678", "password9012", http.StatusNoContent},
+	}
+
+	for i, test := range tests {
+		// each request comes from another host not to reach the limit by host
+		remoteAddr := "192.0.2." + strconv.Itoa(i+1) + ":12345"
+
+		if code := changePassword(s, remoteAddr, test.address, test.currentPassword, test.newPassword); code != test.code {
+			t.Errorf("#%d %s: got status %d, want %d", i, test.address, code, test.code)
+		}
+	}
+}
+
+func TestPasswordChangeLimit(t *testing.T) {
+	s, cleanup := newTestServer(t, "example.com")
+	defer cleanup()
+
+	createTestUser(t, s, "example.com", "bob", "password1234")
+	createTestUser(t, s, "example.com", "alice", "password1234")
+
+	// the limit by the User
+	for i := 0; i < maxFailures; i++ {
+		remoteAddr := "192.0.2." + strconv.Itoa(i+1) + ":12345"
+		if code := changePassword(s, remoteAddr, "bob@example.com", "wrong", "password5678"); code != http.StatusForbidden {
+			t.Fatalf("failure #%d: got status %d, want %d", i, code, http.StatusForbidden)
+		}
+	}
+	if code := changePassword(s, "198.51.100.1:12345", "bob@example.com", "password1234", "password5678"); code != http.StatusTooManyRequests {
+		t.Errorf("correct password after the limit: got status %d, want %d", code, http.StatusTooManyRequests)
+	}
+	if code := changePassword(s, "198.51.100.1:12345", "alice@example.com", "password1234", "password5678"); code != http.StatusNoContent {
+		t.Errorf("another User: got status %d, want %d", code, http.StatusNoContent)
+	}
+
+	// the limit by the host, also for Users which do not exist
+	for i := 0; i < maxFailures; i++ {
+		if code := changePassword(s, "203.0.113.1:12345", "nobody"+strconv.Itoa(i)+"@example.com", "wrong", "password5678"); code != http.StatusForbidden {
+			t.Fatalf("failure #%d: got status %d, want %d", i, code, http.StatusForbidden)
+		}
+	}
+	if code := changePassword(s, "203.0.113.1:12345", "alice@example.com", "password5678", "password9012"); code != http.StatusTooManyRequests {
+		t.Errorf("correct password from the host after the limit: got status %d, want %d", code, http.StatusTooManyRequests)
+	}
+}
+
+func TestFailureLimiterWindow(t *testing.T) {
+	l := newFailureLimiter()
+
+	old := time.Now().Add(-failureWindow - time.Minute)
+	for i := 0; i < maxFailures; i++ {
+		l.failures["key"] = append(l.failures["key"], old)
+	}
+	if l.exceeded("key") {
+		t.Errorf("failures out of the window are counted")
+	}
+	if _, ok := l.failures["key"]; ok {
+		t.Errorf("failures out of the window are not dropped")
+	}
+
+	for i := 0; i < maxFailures-1; i++ {
+		l.add("key")
+	}
+	if l.exceeded("key") {
+		t.Errorf("exceeded after %d failures, want after %d", maxFailures-1, maxFailures)
+	}
+
+	l.add("key")
+	if !l.exceeded("key") {
+		t.Errorf("not exceeded after %d failures", maxFailures)
+	}
+	if !l.exceeded("other", "key") {
+		t.Errorf("not exceeded if any of the keys reaches the limit")
+	}
+
+	l.reset("key")
+	if l.exceeded("key") {
+		t.Errorf("exceeded after reset")
+	}
+}
+
+func TestFailureLimiterResetByUserOnly(t *testing.T) {
+	l := newFailureLimiter()
+
+	for i := 0; i < maxFailures; i++ {
+		l.add("user:bob@example.com", "host:192.0.2.1")
+	}
+
+	// a successful change forgets the failures of the User, but not of the host
+	l.reset("user:bob@example.com")
+
+	if l.exceeded("user:bob@example.com") {
+		t.Errorf("the User is limited after reset")
+	}
+	if !l.exceeded("host:192.0.2.1") {
+		t.Errorf("the host is not limited after reset of the User")
+	}
+}
+
+func TestFailureLimiterSweep(t *testing.T) {
+	l := newFailureLimiter()
+
+	old := time.Now().Add(-failureWindow - time.Minute)
+	l.failures["expired"] = []time.Time{old}
+
+	// expired keys are dropped on insert of another key
+	l.add("key")
+
+	if _, ok := l.failures["expired"]; ok {
+		t.Errorf("expired key is not swept")
+	}
+}
+
+func TestFailureLimiterMaxKeys(t *testing.T) {
+	l := newFailureLimiter()
+
+	for i := 0; i < maxFailureKeys+10; i++ {
+		l.add("host:" + strconv.Itoa(i))
+	}
+
+	if len(l.failures) > maxFailureKeys {
+		t.Errorf("got %d keys, want at most %d", len(l.failures), maxFailureKeys)
+	}
+	if _, ok := l.failures["host:"+strconv.Itoa(maxFailureKeys+9)]; !ok {
+		t.Errorf("the latest key is evicted")
+	}
+}
+
+func TestFailureUserKey(t *testing.T) {
+	if got, want := failureUserKey("Example.COM", "Bob"), failureUserKey("example.com", "bob"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// userJSON represents a User in JSON.
+type userJSON struct {
+	Name        string            `json:"name"`
+	Forwards    []string          `json:"forwards"`
+	Disabled    bool              `json:"disabled"`
+	RejectsMail bool              `json:"rejects_mail"`
+	ExpiresAt   string            `json:"expires_at,omitempty"`
+	AllowNets   []string          `json:"allow_nets,omitempty"`
+	Protocols   []string          `json:"protocols,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// newUserJSON creates a new userJSON instance.
+func newUserJSON(user *mailfull.User) *userJSON {
+	forwards := user.Forwards()
+	if forwards == nil {
+		forwards = []string{}
+	}
+
+	return &userJSON{
+		Name:        user.Name(),
+		Forwards:    forwards,
+		Disabled:    user.Disabled(),
+		RejectsMail: user.RejectsMail(),
+		ExpiresAt:   formatExpiry(user.ExpiresAt()),
+		AllowNets:   user.AllowNets(),
+		Protocols:   user.Protocols(),
+		Metadata:    user.Metadata(),
+	}
+}
+
+// userCreateJSON represents a request body to create a User.
+type userCreateJSON struct {
+	Name     string   `json:"name"`
+	Password string   `json:"password"`
+	Forwards []string `json:"forwards"`
+}
+
+// userUpdateJSON represents a request body to update a User.
+type userUpdateJSON struct {
+	Password    *string           `json:"password"`
+	Forwards    []string          `json:"forwards"`
+	Disabled    *bool             `json:"disabled"`
+	RejectsMail *bool             `json:"rejects_mail"`
+	ExpiresAt   *string           `json:"expires_at"`
+	AllowNets   []string          `json:"allow_nets"`
+	Protocols   []string          `json:"protocols"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+// handleUsers handles "/domains/{domain}/users".
+func (s *Server) handleUsers(w http.ResponseWriter, req *http.Request, domainName string) {
+	switch req.Method {
+	case http.MethodGet:
+		users, err := s.repo.Users(domainName)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		sort.Slice(users, func(i, j int) bool { return users[i].Name() < users[j].Name() })
+
+		res := make([]*userJSON, 0, len(users))
+		for _, user := range users {
+			res = append(res, newUserJSON(user))
+		}
+
+		writeJSON(w, http.StatusOK, res)
+
+	case http.MethodPost:
+		body := &userCreateJSON{}
+		if err := readJSON(req, body); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		hashedPassword, err := mailfull.HashPassword(body.Password)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		user, err := mailfull.NewUser(body.Name, hashedPassword, body.Forwards)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.repo.UserCreate(domainName, user); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, newUserJSON(user))
+
+	default:
+		writeError(w, ErrMethodNotAllowed)
+	}
+}
+
+// handleUser handles "/domains/{domain}/users/{user}".
+func (s *Server) handleUser(w http.ResponseWriter, req *http.Request, domainName, userName string) {
+	switch req.Method {
+	case http.MethodGet:
+		user, err := s.repo.User(domainName, userName)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if user == nil {
+			writeError(w, mailfull.ErrUserNotExist)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newUserJSON(user))
+
+	case http.MethodPut:
+		body := &userUpdateJSON{}
+		if err := readJSON(req, body); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		user, err := s.repo.User(domainName, userName)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if user == nil {
+			writeError(w, mailfull.ErrUserNotExist)
+			return
+		}
+
+		if body.Password != nil {
+			hashedPassword, err := mailfull.HashPassword(*body.Password)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			user.SetHashedPassword(hashedPassword)
+		}
+		if body.Forwards != nil {
+			user.SetForwards(body.Forwards)
+		}
+		if body.Disabled != nil {
+			user.SetDisabled(*body.Disabled)
+		}
+		if body.RejectsMail != nil {
+			user.SetRejectsMail(*body.RejectsMail)
+		}
+		if body.ExpiresAt != nil {
+			expiresAt, err := parseExpiry(*body.ExpiresAt)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			user.SetExpiresAt(expiresAt)
+		}
+		if body.AllowNets != nil {
+			if err := user.SetAllowNets(body.AllowNets); err != nil {
+				writeError(w, err)
+				return
+			}
+		}
+		if body.Protocols != nil {
+			if err := user.SetProtocols(body.Protocols); err != nil {
+				writeError(w, err)
+				return
+			}
+		}
+		if err := setMetadata(user.Metadata(), body.Metadata); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if err := s.repo.UserUpdate(domainName, user); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newUserJSON(user))
+
+	case http.MethodDelete:
+		if err := s.repo.UserRemove(domainName, userName); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, ErrMethodNotAllowed)
+	}
+}
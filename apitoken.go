@@ -0,0 +1,297 @@
+package mailfull
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// APITokenScopeAdmin is the scope of an APIToken that can manage everything.
+const APITokenScopeAdmin = "*"
+
+// Errors for the APIToken.
+var (
+	ErrAPITokenNotExist     = errors.New("APIToken: not exist")
+	ErrAPITokenAlreadyExist = errors.New("APIToken: already exist")
+
+	ErrInvalidAPITokenName    = errors.New("APIToken: name incorrect format")
+	ErrInvalidAPITokenScope   = errors.New("APIToken: scope incorrect format")
+	ErrInvalidFormatAPITokens = errors.New("APIToken: file invalid format")
+)
+
+// APIToken represents a APIToken.
+type APIToken struct {
+	name        string
+	scope       string
+	hashedToken string
+}
+
+// NewAPIToken creates a new APIToken instance.
+// The scope is APITokenScopeAdmin or a domain name.
+func NewAPIToken(name, scope, hashedToken string) (*APIToken, error) {
+	t := &APIToken{}
+
+	if err := t.setName(name); err != nil {
+		return nil, err
+	}
+
+	if err := t.setScope(scope); err != nil {
+		return nil, err
+	}
+
+	t.hashedToken = hashedToken
+
+	return t, nil
+}
+
+// GenerateAPIToken creates a new APIToken instance with a random token.
+// The raw token is returned only here.
+func GenerateAPIToken(name, scope string) (*APIToken, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", err
+	}
+	rawToken := hex.EncodeToString(b)
+
+	t, err := NewAPIToken(name, scope, hashAPIToken(rawToken))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return t, rawToken, nil
+}
+
+// hashAPIToken returns a hashed string of the raw token.
+func hashAPIToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// setName sets the name.
+func (t *APIToken) setName(name string) error {
+	if !regexp.MustCompile(`^[A-Za-z0-9_\-\.]+$`).MatchString(name) {
+		return ErrInvalidAPITokenName
+	}
+
+	t.name = name
+
+	return nil
+}
+
+// setScope sets the scope.
+func (t *APIToken) setScope(scope string) error {
+	if scope != APITokenScopeAdmin {
+		scope = NormalizeDomainName(scope)
+	}
+	if scope != APITokenScopeAdmin && !validDomainName(scope) {
+		return ErrInvalidAPITokenScope
+	}
+
+	t.scope = scope
+
+	return nil
+}
+
+// Name returns name.
+func (t *APIToken) Name() string {
+	return t.name
+}
+
+// Scope returns scope.
+func (t *APIToken) Scope() string {
+	return t.scope
+}
+
+// IsAdmin returns true if the APIToken can manage everything.
+func (t *APIToken) IsAdmin() bool {
+	return t.scope == APITokenScopeAdmin
+}
+
+// HashedToken returns hashedToken.
+func (t *APIToken) HashedToken() string {
+	return t.hashedToken
+}
+
+// Match returns true if the raw token matches the APIToken.
+func (t *APIToken) Match(rawToken string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashAPIToken(rawToken)), []byte(t.hashedToken)) == 1
+}
+
+// APITokens returns a APIToken slice.
+func (r *Repository) APITokens() ([]*APIToken, error) {
+	file, err := os.Open(filepath.Join(r.DirConfigPath(), FileNameAPITokens))
+	if err != nil {
+		if err.(*os.PathError).Err == syscall.ENOENT {
+			return []*APIToken{}, nil
+		}
+
+		return nil, err
+	}
+	defer file.Close()
+
+	apiTokens := make([]*APIToken, 0, 10)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		words := strings.Split(scanner.Text(), ":")
+		if len(words) != 3 {
+			return nil, ErrInvalidFormatAPITokens
+		}
+
+		apiToken, err := NewAPIToken(words[0], words[1], words[2])
+		if err != nil {
+			return nil, err
+		}
+
+		apiTokens = append(apiTokens, apiToken)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return apiTokens, nil
+}
+
+// APIToken returns a APIToken of the input name.
+func (r *Repository) APIToken(apiTokenName string) (*APIToken, error) {
+	apiTokens, err := r.APITokens()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiToken := range apiTokens {
+		if apiToken.Name() == apiTokenName {
+			return apiToken, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// APITokenAuthenticate returns a APIToken that matches the raw token.
+// It returns nil if no APIToken matches.
+func (r *Repository) APITokenAuthenticate(rawToken string) (*APIToken, error) {
+	apiTokens, err := r.APITokens()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiToken := range apiTokens {
+		if apiToken.Match(rawToken) {
+			return apiToken, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// APITokenCreate creates the input APIToken.
+func (r *Repository) APITokenCreate(apiToken *APIToken) error {
+	apiTokens, err := r.APITokens()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range apiTokens {
+		if t.Name() == apiToken.Name() {
+			return ErrAPITokenAlreadyExist
+		}
+	}
+	if !apiToken.IsAdmin() {
+		existDomain, err := r.Domain(apiToken.Scope())
+		if err != nil {
+			return err
+		}
+		if existDomain == nil {
+			return ErrDomainNotExist
+		}
+	}
+
+	apiTokens = append(apiTokens, apiToken)
+
+	if err := r.writeAPITokensFile(apiTokens); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// APITokenRemove removes a APIToken of the input name.
+func (r *Repository) APITokenRemove(apiTokenName string) error {
+	apiTokens, err := r.APITokens()
+	if err != nil {
+		return err
+	}
+
+	idx := -1
+	for i, apiToken := range apiTokens {
+		if apiToken.Name() == apiTokenName {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return ErrAPITokenNotExist
+	}
+
+	apiTokens = append(apiTokens[:idx], apiTokens[idx+1:]...)
+
+	if err := r.writeAPITokensFile(apiTokens); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// apiTokensRemoveByScope removes APITokens of the input scope.
+func (r *Repository) apiTokensRemoveByScope(scope string) error {
+	apiTokens, err := r.APITokens()
+	if err != nil {
+		return err
+	}
+
+	rest := make([]*APIToken, 0, len(apiTokens))
+	for _, apiToken := range apiTokens {
+		if apiToken.Scope() != scope {
+			rest = append(rest, apiToken)
+		}
+	}
+	if len(rest) == len(apiTokens) {
+		return nil
+	}
+
+	if err := r.writeAPITokensFile(rest); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeAPITokensFile writes a APIToken slice to the file.
+func (r *Repository) writeAPITokensFile(apiTokens []*APIToken) error {
+	file, err := os.OpenFile(filepath.Join(r.DirConfigPath(), FileNameAPITokens), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	sort.Slice(apiTokens, func(i, j int) bool { return apiTokens[i].Name() < apiTokens[j].Name() })
+
+	for _, apiToken := range apiTokens {
+		if _, err := fmt.Fprintf(file, "%s:%s:%s\n", apiToken.Name(), apiToken.Scope(), apiToken.HashedToken()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
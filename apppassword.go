@@ -0,0 +1,278 @@
+package mailfull
+
+import (
+	"bufio"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jsimonetti/pwscheme/ssha"
+)
+
+// AppPasswordsMax is the maximum number of AppPasswords of a User.
+// Dovecot looks up a password database for each AppPassword of a User.
+const AppPasswordsMax = 10
+
+// appPasswordLength is the length of a generated AppPassword.
+const appPasswordLength = 16
+
+// Errors for the AppPassword.
+var (
+	ErrAppPasswordNotExist     = errors.New("AppPassword: not exist")
+	ErrAppPasswordAlreadyExist = errors.New("AppPassword: already exist")
+	ErrAppPasswordTooMany      = errors.New("AppPassword: too many")
+
+	ErrInvalidAppPasswordName    = errors.New("AppPassword: name incorrect format")
+	ErrInvalidFormatAppPasswords = errors.New("AppPassword: file invalid format")
+)
+
+// AppPassword represents an application-specific password of a User.
+type AppPassword struct {
+	name           string
+	hashedPassword string
+	createdAt      time.Time
+}
+
+// NewAppPassword creates a new AppPassword instance.
+func NewAppPassword(name, hashedPassword string, createdAt time.Time) (*AppPassword, error) {
+	p := &AppPassword{}
+
+	if err := p.setName(name); err != nil {
+		return nil, err
+	}
+
+	p.hashedPassword = hashedPassword
+	p.createdAt = createdAt
+
+	return p, nil
+}
+
+// GenerateAppPassword creates a new AppPassword instance with a random password.
+// The raw password is returned only here.
+func GenerateAppPassword(name string) (*AppPassword, string, error) {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+
+	buf := make([]byte, 0, appPasswordLength)
+	b := make([]byte, 1)
+	for len(buf) < appPasswordLength {
+		if _, err := rand.Read(b); err != nil {
+			return nil, "", err
+		}
+		// avoid the modulo bias
+		if int(b[0]) >= 256-256%len(letters) {
+			continue
+		}
+		buf = append(buf, letters[int(b[0])%len(letters)])
+	}
+	rawPassword := string(buf)
+
+	hashedPassword, err := HashPassword(rawPassword)
+	if err != nil {
+		return nil, "", err
+	}
+
+	p, err := NewAppPassword(name, hashedPassword, time.Now().Truncate(time.Second))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return p, rawPassword, nil
+}
+
+// setName sets the name.
+func (p *AppPassword) setName(name string) error {
+	if !regexp.MustCompile(`^[A-Za-z0-9_\-\.]+$`).MatchString(name) {
+		return ErrInvalidAppPasswordName
+	}
+
+	p.name = name
+
+	return nil
+}
+
+// Name returns name.
+func (p *AppPassword) Name() string {
+	return p.name
+}
+
+// HashedPassword returns hashedPassword.
+func (p *AppPassword) HashedPassword() string {
+	return p.hashedPassword
+}
+
+// CreatedAt returns the time when the AppPassword was created.
+func (p *AppPassword) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+// ValidatePassword returns true if the input matches the hashed password.
+func (p *AppPassword) ValidatePassword(rawPassword string) bool {
+	ok, _ := ssha.Validate(rawPassword, p.hashedPassword)
+
+	return ok
+}
+
+// AppPasswords returns an AppPassword slice of the User.
+func (r *Repository) AppPasswords(domainName, userName string) ([]*AppPassword, error) {
+	user, err := r.User(domainName, userName)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotExist
+	}
+
+	return r.readAppPasswordsFile(domainName, userName)
+}
+
+// readAppPasswordsFile reads the AppPasswords file of the User.
+func (r *Repository) readAppPasswordsFile(domainName, userName string) ([]*AppPassword, error) {
+	file, err := os.Open(filepath.Join(r.DirMailDataPath, domainName, userName, FileNameUserAppPasswords))
+	if err != nil {
+		if err.(*os.PathError).Err == syscall.ENOENT {
+			return []*AppPassword{}, nil
+		}
+
+		return nil, err
+	}
+	defer file.Close()
+
+	appPasswords := make([]*AppPassword, 0, AppPasswordsMax)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// name:hashedPassword:createdAt
+		words := strings.SplitN(scanner.Text(), ":", 3)
+		if len(words) != 3 {
+			return nil, ErrInvalidFormatAppPasswords
+		}
+
+		createdAt, err := time.Parse(time.RFC3339, words[2])
+		if err != nil {
+			return nil, ErrInvalidFormatAppPasswords
+		}
+
+		appPassword, err := NewAppPassword(words[0], words[1], createdAt)
+		if err != nil {
+			return nil, err
+		}
+
+		appPasswords = append(appPasswords, appPassword)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return appPasswords, nil
+}
+
+// AppPasswordCreate creates the input AppPassword of the User.
+func (r *Repository) AppPasswordCreate(domainName, userName string, appPassword *AppPassword) error {
+	appPasswords, err := r.AppPasswords(domainName, userName)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range appPasswords {
+		if p.Name() == appPassword.Name() {
+			return ErrAppPasswordAlreadyExist
+		}
+	}
+	if len(appPasswords) >= AppPasswordsMax {
+		return ErrAppPasswordTooMany
+	}
+
+	appPasswords = append(appPasswords, appPassword)
+
+	if err := r.writeAppPasswordsFile(domainName, userName, appPasswords); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AppPasswordRemove removes an AppPassword of the input name from the User.
+func (r *Repository) AppPasswordRemove(domainName, userName, appPasswordName string) error {
+	appPasswords, err := r.AppPasswords(domainName, userName)
+	if err != nil {
+		return err
+	}
+
+	idx := -1
+	for i, appPassword := range appPasswords {
+		if appPassword.Name() == appPasswordName {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return ErrAppPasswordNotExist
+	}
+
+	appPasswords = append(appPasswords[:idx], appPasswords[idx+1:]...)
+
+	if err := r.writeAppPasswordsFile(domainName, userName, appPasswords); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AppPasswordAuthenticate returns a User of the input name if the password matches one of the AppPasswords.
+// It returns ErrPasswordMismatch also if the User cannot log in.
+func (r *Repository) AppPasswordAuthenticate(domainName, userName, rawPassword string) (*User, error) {
+	user, err := r.ActiveUser(domainName, userName)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		ssha.Validate(rawPassword, dummyHashedPassword)
+		return nil, ErrPasswordMismatch
+	}
+
+	appPasswords, err := r.readAppPasswordsFile(domainName, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, appPassword := range appPasswords {
+		if appPassword.ValidatePassword(rawPassword) {
+			return user, nil
+		}
+	}
+
+	return nil, ErrPasswordMismatch
+}
+
+// writeAppPasswordsFile writes an AppPassword slice to the AppPasswords file of the User.
+func (r *Repository) writeAppPasswordsFile(domainName, userName string, appPasswords []*AppPassword) error {
+	if !validDomainName(domainName) {
+		return ErrInvalidDomainName
+	}
+	if !validUserName(userName) {
+		return ErrInvalidUserName
+	}
+
+	file, err := os.OpenFile(filepath.Join(r.DirMailDataPath, domainName, userName, FileNameUserAppPasswords), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := file.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for _, appPassword := range appPasswords {
+		if _, err := fmt.Fprintf(file, "%s:%s:%s\n", appPassword.Name(), appPassword.HashedPassword(), appPassword.CreatedAt().Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package mailfull
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// templateAutoconfig is the default template of config-v1.1.xml for Thunderbird.
+const templateAutoconfig = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Generated by mailfull {{.Version}} -->
+<clientConfig version="1.1">
+  <emailProvider id="{{html .Domain}}">
+    <domain>{{html .Domain}}</domain>
+    <displayName>{{html .Domain}}</displayName>
+    <displayShortName>{{html .Domain}}</displayShortName>
+{{- if has .Dovecot.Protocols "imap"}}
+    <incomingServer type="imap">
+      <hostname>{{html .Hostname}}</hostname>
+      <port>993</port>
+      <socketType>SSL</socketType>
+      <authentication>password-cleartext</authentication>
+      <username>%EMAILADDRESS%</username>
+    </incomingServer>
+{{- end}}
+{{- if has .Dovecot.Protocols "pop3"}}
+    <incomingServer type="pop3">
+      <hostname>{{html .Hostname}}</hostname>
+      <port>995</port>
+      <socketType>SSL</socketType>
+      <authentication>password-cleartext</authentication>
+      <username>%EMAILADDRESS%</username>
+    </incomingServer>
+{{- end}}
+    <outgoingServer type="smtp">
+      <hostname>{{html .Hostname}}</hostname>
+      <port>465</port>
+      <socketType>SSL</socketType>
+      <authentication>password-cleartext</authentication>
+      <username>%EMAILADDRESS%</username>
+    </outgoingServer>
+    <outgoingServer type="smtp">
+      <hostname>{{html .Hostname}}</hostname>
+      <port>587</port>
+      <socketType>STARTTLS</socketType>
+      <authentication>password-cleartext</authentication>
+      <username>%EMAILADDRESS%</username>
+    </outgoingServer>
+  </emailProvider>
+</clientConfig>
+`
+
+// templateAutodiscover is the default template of autodiscover.xml for Outlook.
+const templateAutodiscover = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Generated by mailfull {{.Version}} -->
+<Autodiscover xmlns="http://schemas.microsoft.com/exchange/autodiscover/responseschema/2006">
+  <Response xmlns="http://schemas.microsoft.com/exchange/autodiscover/outlook/responseschema/2006a">
+    <Account>
+      <AccountType>email</AccountType>
+      <Action>settings</Action>
+{{- if has .Dovecot.Protocols "imap"}}
+      <Protocol>
+        <Type>IMAP</Type>
+        <Server>{{html .Hostname}}</Server>
+        <Port>993</Port>
+        <DomainRequired>off</DomainRequired>
+        <SPA>off</SPA>
+        <SSL>on</SSL>
+        <AuthRequired>on</AuthRequired>
+      </Protocol>
+{{- end}}
+{{- if has .Dovecot.Protocols "pop3"}}
+      <Protocol>
+        <Type>POP3</Type>
+        <Server>{{html .Hostname}}</Server>
+        <Port>995</Port>
+        <DomainRequired>off</DomainRequired>
+        <SPA>off</SPA>
+        <SSL>on</SSL>
+        <AuthRequired>on</AuthRequired>
+      </Protocol>
+{{- end}}
+      <Protocol>
+        <Type>SMTP</Type>
+        <Server>{{html .Hostname}}</Server>
+        <Port>465</Port>
+        <DomainRequired>off</DomainRequired>
+        <SPA>off</SPA>
+        <SSL>on</SSL>
+        <AuthRequired>on</AuthRequired>
+      </Protocol>
+    </Account>
+  </Response>
+</Autodiscover>
+`
+
+// autoconfigFiles is a map of file names in the web root to the templates.
+var autoconfigFiles = map[string]string{
+	FileNameAutoconfig:   FileNameTemplateAutoconfig,
+	FileNameAutodiscover: FileNameTemplateAutodiscover,
+}
+
+// generateAutoconfig generates configurations for mail clients of each Domain and AliasDomain
+// into "AutoconfigWebRoot/<domain>/". It does nothing if AutoconfigWebRoot is empty.
+func (r *Repository) generateAutoconfig(rd *repoData) error {
+	if r.AutoconfigWebRoot == "" {
+		return nil
+	}
+	if r.mailHostname() == "" {
+		return ErrMailHostnameNotSet
+	}
+
+	if err := os.MkdirAll(r.AutoconfigWebRoot, 0755); err != nil {
+		return err
+	}
+
+	domainNames := map[string]bool{}
+	for _, entry := range dbDomains(rd) {
+		domainNames[entry.key] = true
+	}
+
+	for domainName := range domainNames {
+		data := r.configTemplateData()
+		data.Domain = domainName
+
+		for fileName, templateName := range autoconfigFiles {
+			text, err := r.executeConfigTemplate(templateName, data)
+			if err != nil {
+				return err
+			}
+
+			if err := writeWebRootFile(filepath.Join(r.AutoconfigWebRoot, domainName, fileName), text); err != nil {
+				return err
+			}
+		}
+	}
+
+	// remove files of removed or disabled domains
+	fileInfos, err := ioutil.ReadDir(r.AutoconfigWebRoot)
+	if err != nil {
+		return err
+	}
+	for _, fileInfo := range fileInfos {
+		name := fileInfo.Name()
+		if !fileInfo.IsDir() || domainNames[name] || !validDomainName(name) {
+			continue
+		}
+
+		if err := removeWebRootFiles(filepath.Join(r.AutoconfigWebRoot, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeWebRootFile writes the text to the file readable by a web server.
+func writeWebRootFile(path, text string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, []byte(text), 0644)
+}
+
+// removeWebRootFiles removes the generated files in the directory,
+// and the directories that become empty.
+func removeWebRootFiles(dirPath string) error {
+	for fileName := range autoconfigFiles {
+		path := filepath.Join(dirPath, fileName)
+
+		if err := os.Remove(path); err != nil && err.(*os.PathError).Err != syscall.ENOENT {
+			return err
+		}
+		removeEmptyDir(filepath.Dir(path))
+	}
+
+	removeEmptyDir(dirPath)
+
+	return nil
+}
+
+// removeEmptyDir removes the directory if it is empty.
+func removeEmptyDir(dirPath string) {
+	fileInfos, err := ioutil.ReadDir(dirPath)
+	if err != nil || len(fileInfos) != 0 {
+		return
+	}
+
+	os.Remove(dirPath)
+}
@@ -14,8 +14,18 @@ type CatchAllUser struct {
 
 // NewCatchAllUser creates a new CatchAllUser instance.
 func NewCatchAllUser(name string) (*CatchAllUser, error) {
-	if !validCatchAllUserName(name) {
-		return nil, ErrInvalidCatchAllUserName
+	if err := validationError(ErrInvalidCatchAllUserName, nameRule(name)); err != nil {
+		return nil, err
+	}
+
+	return newCatchAllUser(name)
+}
+
+// newCatchAllUser creates a new CatchAllUser instance of an existing CatchAllUser.
+// The name written before the rules of names is accepted as is.
+func newCatchAllUser(name string) (*CatchAllUser, error) {
+	if err := validationError(ErrInvalidCatchAllUserName, existingNameRule(name)); err != nil {
+		return nil, err
 	}
 
 	cu := &CatchAllUser{
@@ -59,7 +69,7 @@ func (r *Repository) CatchAllUser(domainName string) (*CatchAllUser, error) {
 		return nil, nil
 	}
 
-	catchAllUser, err := NewCatchAllUser(name)
+	catchAllUser, err := newCatchAllUser(name)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,172 @@
+package mailfull
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of name collisions.
+const (
+	collisionCase      = "collide case-insensitively"
+	collisionDelimiter = "collide by the recipient delimiter"
+)
+
+// checkNameCollision returns an error if the input name equals to a User or a AliasUser of the Domain case-insensitively.
+// A collision by the recipient delimiter is not an error because Postfix looks up the exact address first.
+func (r *Repository) checkNameCollision(domainName, name string) error {
+	users, err := r.Users(domainName)
+	if err != nil {
+		return err
+	}
+	aliasUsers, err := r.AliasUsers(domainName)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if strings.EqualFold(user.Name(), name) {
+			return ErrUserAlreadyExist
+		}
+	}
+	for _, aliasUser := range aliasUsers {
+		if strings.EqualFold(aliasUser.Name(), name) {
+			return ErrAliasUserAlreadyExist
+		}
+	}
+
+	return nil
+}
+
+// checkDomainNameCollision returns an error if the input name equals to a Domain or a AliasDomain case-insensitively.
+func (r *Repository) checkDomainNameCollision(name string) error {
+	domains, err := r.Domains()
+	if err != nil {
+		return err
+	}
+	aliasDomains, err := r.AliasDomains()
+	if err != nil {
+		return err
+	}
+
+	for _, domain := range domains {
+		if strings.EqualFold(domain.Name(), name) {
+			return ErrDomainAlreadyExist
+		}
+	}
+	for _, aliasDomain := range aliasDomains {
+		if strings.EqualFold(aliasDomain.Name(), name) {
+			return ErrAliasDomainAlreadyExist
+		}
+	}
+
+	return nil
+}
+
+// Check returns problems of the Repository which make lookups of the generated databases ambiguous
+// or names which violate the rules for new entries, and warnings of names which may receive mails to others.
+// It returns empty slices if there are no problems and no warnings.
+func (r *Repository) Check() ([]string, []string, error) {
+	rd, err := r.repoData()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rd.sortAll()
+
+	problems := r.checkRules(rd)
+	warnings := []string{}
+
+	domainNames := []string{}
+	for _, domain := range rd.Domains {
+		if domain.Name() != NormalizeDomainName(domain.Name()) {
+			problems = append(problems, fmt.Sprintf("Domain: %s is not in lower case", domain.Name()))
+		}
+		domainNames = append(domainNames, domain.Name())
+	}
+	for _, aliasDomain := range rd.AliasDomains {
+		domainNames = append(domainNames, aliasDomain.Name())
+	}
+	sort.Strings(domainNames)
+
+	for i := range domainNames {
+		for j := i + 1; j < len(domainNames); j++ {
+			if strings.EqualFold(domainNames[i], domainNames[j]) {
+				problems = append(problems, fmt.Sprintf("Domain: %s and %s %s", domainNames[i], domainNames[j], collisionCase))
+			}
+		}
+	}
+
+	for _, domain := range rd.Domains {
+		names := []string{}
+		for _, user := range domain.Users {
+			names = append(names, user.Name())
+		}
+		for _, aliasUser := range domain.AliasUsers {
+			names = append(names, aliasUser.Name())
+		}
+		sort.Strings(names)
+
+		for i := range names {
+			for j := i + 1; j < len(names); j++ {
+				switch {
+				case strings.EqualFold(names[i], names[j]):
+					problems = append(problems, fmt.Sprintf("Address: %s@%s and %s@%s %s", names[i], domain.Name(), names[j], domain.Name(), collisionCase))
+				case r.collidesByDelimiter(names[i], names[j]):
+					warnings = append(warnings, fmt.Sprintf("Address: %s@%s and %s@%s %s", names[i], domain.Name(), names[j], domain.Name(), collisionDelimiter))
+				}
+			}
+		}
+	}
+
+	return problems, warnings, nil
+}
+
+// checkRules returns problems of names which violate the rules for new entries.
+// They are written before the rules, and are read leniently.
+func (r *Repository) checkRules(rd *repoData) []string {
+	problems := []string{}
+
+	addProblem := func(err error, name string) {
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%v: %s", err, name))
+		}
+	}
+	addAddressProblem := func(address, context string, sender bool) {
+		a, err := ParseAddress(address)
+		if err == nil && sender {
+			err = validationError(ErrInvalidAliasUserSender, nameRule(a.LocalPart()))
+		}
+		if err == nil {
+			err = a.validate()
+		}
+		addProblem(err, address+" "+context)
+	}
+
+	for _, domain := range rd.Domains {
+		addProblem(validationError(ErrInvalidDomainName, domainNameRule(domain.Name())), domain.Name())
+
+		for _, user := range domain.Users {
+			addProblem(validationError(ErrInvalidUserName, userNameRule(user.Name())), user.Name()+"@"+domain.Name())
+		}
+		for _, aliasUser := range domain.AliasUsers {
+			address := aliasUser.Name() + "@" + domain.Name()
+
+			addProblem(validationError(ErrInvalidAliasUserName, nameRule(aliasUser.Name())), address)
+			for _, target := range aliasUser.Targets() {
+				addAddressProblem(target, "in the targets of "+address, false)
+			}
+			for _, sender := range aliasUser.Senders() {
+				addAddressProblem(sender, "in the senders of "+address, true)
+			}
+		}
+		if domain.CatchAllUser != nil {
+			addProblem(validationError(ErrInvalidCatchAllUserName, nameRule(domain.CatchAllUser.Name())), domain.CatchAllUser.Name()+"@"+domain.Name())
+		}
+	}
+	for _, aliasDomain := range rd.AliasDomains {
+		addProblem(validationError(ErrInvalidAliasDomainName, domainNameRule(aliasDomain.Name())), aliasDomain.Name())
+	}
+
+	return problems
+}
@@ -55,8 +55,8 @@ func (c *CmdAliasDomainAdd) Run(args []string) int {
 		return 1
 	}
 
-	aliasDomainName := args[0]
-	targetDomainName := args[1]
+	aliasDomainName := mailfull.NormalizeDomainName(args[0])
+	targetDomainName := mailfull.NormalizeDomainName(args[1])
 
 	repo, err := mailfull.OpenRepository(".")
 	if err != nil {
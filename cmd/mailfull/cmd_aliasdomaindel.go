@@ -53,7 +53,7 @@ func (c *CmdAliasDomainDel) Run(args []string) int {
 		return 1
 	}
 
-	aliasDomainName := args[0]
+	aliasDomainName := mailfull.NormalizeDomainName(args[0])
 
 	repo, err := mailfull.OpenRepository(".")
 	if err != nil {
@@ -26,6 +26,7 @@ Usage:
 
 Description:
     %s
+    Internationalized domain names are shown in Unicode.
 
 Optional Args:
     domain
@@ -46,7 +47,7 @@ func (c *CmdAliasDomains) Run(args []string) int {
 
 	targetDomainName := ""
 	if len(args) == 1 {
-		targetDomainName = args[0]
+		targetDomainName = mailfull.NormalizeDomainName(args[0])
 	}
 
 	repo, err := mailfull.OpenRepository(".")
@@ -65,10 +66,10 @@ func (c *CmdAliasDomains) Run(args []string) int {
 	for _, aliasDomain := range aliasDomains {
 		if targetDomainName != "" {
 			if aliasDomain.Target() == targetDomainName {
-				fmt.Fprintf(c.UI.Writer, "%s\n", aliasDomain.Name())
+				fmt.Fprintf(c.UI.Writer, "%s\n", mailfull.DomainNameToUnicode(aliasDomain.Name()))
 			}
 		} else {
-			fmt.Fprintf(c.UI.Writer, "%s\n", aliasDomain.Name())
+			fmt.Fprintf(c.UI.Writer, "%s\n", mailfull.DomainNameToUnicode(aliasDomain.Name()))
 		}
 	}
 
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdAliasSendersAdd represents a CmdAliasSendersAdd.
+type CmdAliasSendersAdd struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdAliasSendersAdd) Synopsis() string {
+	return "Permit users to send as a aliasuser."
+}
+
+// Help returns long-form help text.
+func (c *CmdAliasSendersAdd) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] address sender [sender...]
+
+Description:
+    %s
+    The senders are added to the sender login map (smtpd_sender_login_maps)
+    in addition to the users that are targets of the aliasuser.
+
+Required Args:
+    address
+        The email address of the aliasuser.
+    sender
+        The email addresses of users that you want to permit.
+
+Optional Args:
+    -n
+        Don't update databases.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdAliasSendersAdd) Run(args []string) int {
+	noCommit, err := noCommitFlag(&args)
+	if err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	if len(args) < 2 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	senders := args[1:]
+	aliasUserName := address.LocalPart()
+	domainName := address.Domain()
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	for _, sender := range senders {
+		if err := repo.AliasUserSenderAdd(domainName, aliasUserName, sender); err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+	}
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabases(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
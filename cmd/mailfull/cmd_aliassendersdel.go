@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdAliasSendersDel represents a CmdAliasSendersDel.
+type CmdAliasSendersDel struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdAliasSendersDel) Synopsis() string {
+	return "Revoke permissions of users to send as a aliasuser."
+}
+
+// Help returns long-form help text.
+func (c *CmdAliasSendersDel) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] address sender [sender...]
+
+Description:
+    %s
+    The targets of the aliasuser that are users are still permitted.
+
+Required Args:
+    address
+        The email address of the aliasuser.
+    sender
+        The email addresses of users that you want to revoke.
+
+Optional Args:
+    -n
+        Don't update databases.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdAliasSendersDel) Run(args []string) int {
+	noCommit, err := noCommitFlag(&args)
+	if err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	if len(args) < 2 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	senders := args[1:]
+	aliasUserName := address.LocalPart()
+	domainName := address.Domain()
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	for _, sender := range senders {
+		if err := repo.AliasUserSenderRemove(domainName, aliasUserName, sender); err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+	}
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabases(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
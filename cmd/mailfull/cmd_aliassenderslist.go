@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdAliasSendersList represents a CmdAliasSendersList.
+type CmdAliasSendersList struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdAliasSendersList) Synopsis() string {
+	return "Show users permitted to send as a aliasuser."
+}
+
+// Help returns long-form help text.
+func (c *CmdAliasSendersList) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s address
+
+Description:
+    %s
+    Only users added by "aliassenders add" are shown.
+
+Required Args:
+    address
+        The email address of the aliasuser.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdAliasSendersList) Run(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	aliasUserName := address.LocalPart()
+	domainName := address.Domain()
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	aliasUser, err := repo.AliasUser(domainName, aliasUserName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if aliasUser == nil {
+		c.Meta.Errorf("%v\n", mailfull.ErrAliasUserNotExist)
+		return 1
+	}
+
+	for _, sender := range aliasUser.Senders() {
+		fmt.Fprintf(c.UI.Writer, "%s\n", mailfull.AddressToUnicode(sender))
+	}
+
+	return 0
+}
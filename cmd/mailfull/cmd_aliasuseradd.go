@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/directorz/mailfull-go"
 	"github.com/directorz/mailfull-go/cmd"
@@ -56,16 +55,14 @@ func (c *CmdAliasUserAdd) Run(args []string) int {
 		return 1
 	}
 
-	address := args[0]
-	targets := args[1:]
-
-	words := strings.Split(address, "@")
-	if len(words) != 2 {
-		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
 		return 1
 	}
-	aliasUserName := words[0]
-	domainName := words[1]
+	targets := args[1:]
+	aliasUserName := address.LocalPart()
+	domainName := address.Domain()
 
 	repo, err := mailfull.OpenRepository(".")
 	if err != nil {
@@ -44,7 +44,7 @@ func (c *CmdAliasUsers) Run(args []string) int {
 		return 1
 	}
 
-	targetDomainName := args[0]
+	targetDomainName := mailfull.NormalizeDomainName(args[0])
 
 	repo, err := mailfull.OpenRepository(".")
 	if err != nil {
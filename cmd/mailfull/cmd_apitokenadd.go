@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdAPITokenAdd represents a CmdAPITokenAdd.
+type CmdAPITokenAdd struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdAPITokenAdd) Synopsis() string {
+	return "Create a new API token."
+}
+
+// Help returns long-form help text.
+func (c *CmdAPITokenAdd) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-domain domain] name
+
+Description:
+    %s
+    The token is shown only once. Keep it in a safe place.
+
+Required Args:
+    name
+        The token name that you want to create.
+
+Optional Args:
+    -domain
+        Restrict the token to manage users and aliasusers inside the domain.
+        Without this option, the token can manage everything.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdAPITokenAdd) Run(args []string) int {
+	scope := mailfull.APITokenScopeAdmin
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.StringVar(&scope, "domain", scope, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+	args = flagSet.Args()
+
+	if len(args) != 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	apiTokenName := args[0]
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	apiToken, rawToken, err := mailfull.GenerateAPIToken(apiTokenName, scope)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if err := repo.APITokenCreate(apiToken); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(c.UI.Writer, "%s\n", rawToken)
+
+	return 0
+}
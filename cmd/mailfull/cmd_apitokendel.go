@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdAPITokenDel represents a CmdAPITokenDel.
+type CmdAPITokenDel struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdAPITokenDel) Synopsis() string {
+	return "Delete and revoke an API token."
+}
+
+// Help returns long-form help text.
+func (c *CmdAPITokenDel) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s name
+
+Description:
+    %s
+
+Required Args:
+    name
+        The token name that you want to delete.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdAPITokenDel) Run(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	apiTokenName := args[0]
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if err := repo.APITokenRemove(apiTokenName); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdAPITokens represents a CmdAPITokens.
+type CmdAPITokens struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdAPITokens) Synopsis() string {
+	return "Show API tokens."
+}
+
+// Help returns long-form help text.
+func (c *CmdAPITokens) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s
+
+Description:
+    %s
+    Each line shows the token name and its scope.
+    The scope "*" means the token can manage everything.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdAPITokens) Run(args []string) int {
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	apiTokens, err := repo.APITokens()
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	for _, apiToken := range apiTokens {
+		fmt.Fprintf(c.UI.Writer, "%s %s\n", apiToken.Name(), apiToken.Scope())
+	}
+
+	return 0
+}
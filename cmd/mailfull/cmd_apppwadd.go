@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdAppPwAdd represents a CmdAppPwAdd.
+type CmdAppPwAdd struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdAppPwAdd) Synopsis() string {
+	return "Create an application password of a user."
+}
+
+// Help returns long-form help text.
+func (c *CmdAppPwAdd) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] address name
+
+Description:
+    %s
+    The generated password is shown only once. It can be used instead of the password of the user.
+    A user can have up to %d application passwords.
+
+Required Args:
+    address
+        The email address of the user.
+    name
+        The name of the application password. (e.g. "phone")
+
+Optional Args:
+    -n
+        Don't update databases.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis(),
+		mailfull.AppPasswordsMax)
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdAppPwAdd) Run(args []string) int {
+	noCommit, err := noCommitFlag(&args)
+	if err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	if len(args) != 2 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	userName := address.LocalPart()
+	domainName := address.Domain()
+	appPasswordName := args[1]
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	appPassword, rawPassword, err := mailfull.GenerateAppPassword(appPasswordName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if err := repo.AppPasswordCreate(domainName, userName, appPassword); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(c.UI.Writer, "%s\n", rawPassword)
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabasePasswords(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdAppPwList represents a CmdAppPwList.
+type CmdAppPwList struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdAppPwList) Synopsis() string {
+	return "Show application passwords of a user."
+}
+
+// Help returns long-form help text.
+func (c *CmdAppPwList) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s address
+
+Description:
+    %s
+    Each line shows the name and the creation time.
+
+Required Args:
+    address
+        The email address of the user.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdAppPwList) Run(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	userName := address.LocalPart()
+	domainName := address.Domain()
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	appPasswords, err := repo.AppPasswords(domainName, userName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	for _, appPassword := range appPasswords {
+		fmt.Fprintf(c.UI.Writer, "%s %s\n", appPassword.Name(), appPassword.CreatedAt().Local().Format(time.RFC3339))
+	}
+
+	return 0
+}
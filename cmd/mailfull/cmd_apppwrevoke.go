@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdAppPwRevoke represents a CmdAppPwRevoke.
+type CmdAppPwRevoke struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdAppPwRevoke) Synopsis() string {
+	return "Revoke an application password of a user."
+}
+
+// Help returns long-form help text.
+func (c *CmdAppPwRevoke) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] address name
+
+Description:
+    %s
+
+Required Args:
+    address
+        The email address of the user.
+    name
+        The name of the application password.
+
+Optional Args:
+    -n
+        Don't update databases.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdAppPwRevoke) Run(args []string) int {
+	noCommit, err := noCommitFlag(&args)
+	if err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	if len(args) != 2 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	userName := address.LocalPart()
+	domainName := address.Domain()
+	appPasswordName := args[1]
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if err := repo.AppPasswordRemove(domainName, userName, appPasswordName); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabasePasswords(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
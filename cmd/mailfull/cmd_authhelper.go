@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// Exit statuses of the checkpassword protocol.
+const (
+	checkpasswordFailure     = 1
+	checkpasswordTempFailure = 111
+)
+
+// CmdAuthHelper represents a CmdAuthHelper.
+type CmdAuthHelper struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdAuthHelper) Synopsis() string {
+	return "Authenticate a user by the checkpassword protocol for Dovecot."
+}
+
+// Help returns long-form help text.
+func (c *CmdAuthHelper) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-repo path] program [args...]
+
+Description:
+    %s
+    It reads "address\0password\0" from the file descriptor 3,
+    and executes the program if the password is correct.
+    The password is verified with the repository directly, so no commit is needed.
+    Users of disabled domains cannot log in. Application passwords are also accepted.
+    The allowed protocols and networks of each user are checked with SERVICE and TCPREMOTEIP.
+
+    dovecot.conf:
+        passdb {
+          driver = checkpassword
+          args = /path/to/mailfull %s -repo /path/to/repo
+        }
+        userdb {
+          driver = prefetch
+        }
+
+Required Args:
+    program
+        The program executed on success. (e.g. checkpassword-reply)
+        Dovecot appends it to the args.
+
+Optional Args:
+    -repo
+        The path of the repository. (default: ".")
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis(),
+		c.SubCmdName)
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdAuthHelper) Run(args []string) int {
+	repoPath := "."
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.StringVar(&repoPath, "repo", repoPath, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return checkpasswordTempFailure
+	}
+	args = flagSet.Args()
+
+	if len(args) < 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return checkpasswordTempFailure
+	}
+
+	input, err := readCheckpasswordInput()
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return checkpasswordTempFailure
+	}
+	if len(input) < 2 {
+		c.Meta.Errorf("invalid input\n")
+		return checkpasswordTempFailure
+	}
+
+	address, err := mailfull.ParseAddress(input[0])
+	if err != nil {
+		return checkpasswordFailure
+	}
+	rawPassword := input[1]
+
+	userName := address.LocalPart()
+	domainName := address.Domain()
+
+	repo, err := mailfull.OpenRepository(repoPath)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return checkpasswordTempFailure
+	}
+
+	// AUTHORIZED=1 means a userdb lookup, the password is not given.
+	authorized := os.Getenv("AUTHORIZED") == "1"
+
+	var user *mailfull.User
+
+	if authorized {
+		user, err = repo.ActiveUser(domainName, userName)
+		if err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return checkpasswordTempFailure
+		}
+		if user == nil {
+			return checkpasswordFailure
+		}
+	} else {
+		user, err = repo.UserAuthenticate(domainName, userName, rawPassword)
+		if err == mailfull.ErrPasswordMismatch {
+			user, err = repo.AppPasswordAuthenticate(domainName, userName, rawPassword)
+		}
+		if err != nil {
+			if err == mailfull.ErrPasswordMismatch {
+				return checkpasswordFailure
+			}
+			c.Meta.Errorf("%v\n", err)
+			return checkpasswordTempFailure
+		}
+	}
+
+	// SERVICE and TCPREMOTEIP are given by Dovecot.
+	if service := os.Getenv("SERVICE"); service != "" && !user.AllowsProtocol(service) {
+		return checkpasswordFailure
+	}
+	if !user.AllowsIP(net.ParseIP(os.Getenv("TCPREMOTEIP"))) {
+		return checkpasswordFailure
+	}
+
+	programPath, err := exec.LookPath(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return checkpasswordTempFailure
+	}
+
+	vars := map[string]string{
+		"USER":       address.String(),
+		"HOME":       filepath.Join(repo.DirMailDataPath, domainName, userName),
+		"userdb_uid": strconv.Itoa(repo.UID()),
+		"userdb_gid": strconv.Itoa(repo.GID()),
+		"EXTRA":      "userdb_uid userdb_gid",
+	}
+	if authorized {
+		vars["AUTHORIZED"] = "2"
+	}
+	for key, value := range vars {
+		if err := os.Setenv(key, value); err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return checkpasswordTempFailure
+		}
+	}
+
+	if err := syscall.Exec(programPath, args, os.Environ()); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return checkpasswordTempFailure
+	}
+
+	return 0
+}
+
+// readCheckpasswordInput reads NUL separated fields from the file descriptor 3.
+func readCheckpasswordInput() ([]string, error) {
+	file := os.NewFile(3, "checkpassword")
+	if file == nil {
+		return nil, fmt.Errorf("file descriptor 3 is not available")
+	}
+	defer file.Close()
+
+	buf := &bytes.Buffer{}
+	if _, err := io.Copy(buf, io.LimitReader(file, 512)); err != nil {
+		return nil, err
+	}
+
+	return strings.Split(buf.String(), "\x00"), nil
+}
@@ -43,7 +43,7 @@ func (c *CmdCatchAll) Run(args []string) int {
 		return 1
 	}
 
-	domainName := args[0]
+	domainName := mailfull.NormalizeDomainName(args[0])
 
 	repo, err := mailfull.OpenRepository(".")
 	if err != nil {
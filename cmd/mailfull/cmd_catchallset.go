@@ -55,7 +55,7 @@ func (c *CmdCatchAllSet) Run(args []string) int {
 		return 1
 	}
 
-	domainName := args[0]
+	domainName := mailfull.NormalizeDomainName(args[0])
 	userName := args[1]
 
 	repo, err := mailfull.OpenRepository(".")
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdCheck represents a CmdCheck.
+type CmdCheck struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdCheck) Synopsis() string {
+	return "Report names colliding in the lookups of Postfix."
+}
+
+// Help returns long-form help text.
+func (c *CmdCheck) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s
+
+Description:
+    %s
+    Postfix compares addresses case-insensitively, and strips an extension after the recipient delimiter.
+    Domains and addresses that collide case-insensitively, and domains not in lower case are reported as problems.
+    Addresses that collide by the recipient delimiter (e.g. foo and foo-bar) are reported as warnings.
+    Names created by older versions are still usable, but are reported as problems if they violate the current rules.
+    The exit status is 1 if any problems are found.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdCheck) Run(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	problems, warnings, err := repo.Check()
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintf(c.UI.Writer, "%s\n", problem)
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(c.UI.Writer, "[WARN] %s\n", warning)
+	}
+
+	if len(problems) > 0 {
+		return 1
+	}
+
+	return 0
+}
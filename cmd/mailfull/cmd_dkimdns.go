@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdDKIMDNS represents a CmdDKIMDNS.
+type CmdDKIMDNS struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdDKIMDNS) Synopsis() string {
+	return "Show the DKIM TXT record of a domain."
+}
+
+// Help returns long-form help text.
+func (c *CmdDKIMDNS) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s domain
+
+Description:
+    %s
+    The record is written in the zone file format.
+    An alias domain is signed with the key of the target domain.
+
+Required Args:
+    domain
+        The domain name or the alias domain name.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdDKIMDNS) Run(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	domainName := mailfull.NormalizeDomainName(args[0])
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	targetDomainName := domainName
+
+	aliasDomain, err := repo.AliasDomain(domainName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if aliasDomain != nil {
+		targetDomainName = aliasDomain.Target()
+	}
+
+	key, err := repo.DKIMKey(targetDomainName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if key == nil {
+		c.Meta.Errorf("%v\n", mailfull.ErrDKIMKeyNotExist)
+		return 1
+	}
+
+	fmt.Fprintf(c.UI.Writer, "%s\n", formatDNSRecord(&mailfull.DNSRecord{
+		Name:  key.RecordName(domainName),
+		Type:  "TXT",
+		Value: key.TXTRecord(),
+	}))
+
+	return 0
+}
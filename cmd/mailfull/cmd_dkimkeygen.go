@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdDKIMKeygen represents a CmdDKIMKeygen.
+type CmdDKIMKeygen struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdDKIMKeygen) Synopsis() string {
+	return "Generate a DKIM signing key of a domain."
+}
+
+// Help returns long-form help text.
+func (c *CmdDKIMKeygen) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] [-t type] [-b bits] [-f] domain [selector]
+
+Description:
+    %s
+    The key is stored in the domain directory, and is used also for the alias domains of the domain.
+    KeyTable and SigningTable for OpenDKIM, and selector_map and path_map for rspamd
+    are generated in the database directory.
+    The signing daemon must be able to read the key, see "dir_dkim_keys" and "dkim_key_group" in the config.
+    Publish the TXT record shown by "dkim dns" before the key is used for signing.
+
+Required Args:
+    domain
+        The domain name.
+
+Optional Args:
+    selector
+        The selector of the key. (default: "%s")
+    -n
+        Don't update databases.
+    -t
+        The type of the key. "rsa" or "ed25519". (default: "rsa")
+    -b
+        The bits of the RSA key. (default: 2048)
+    -f
+        Replace the existing key.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis(),
+		mailfull.DefaultDKIMSelector)
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdDKIMKeygen) Run(args []string) int {
+	noCommit := false
+	keyType := mailfull.DKIMKeyTypeRSA
+	bits := 2048
+	force := false
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.BoolVar(&noCommit, "n", noCommit, "")
+	flagSet.StringVar(&keyType, "t", keyType, "")
+	flagSet.IntVar(&bits, "b", bits, "")
+	flagSet.BoolVar(&force, "f", force, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+	args = flagSet.Args()
+
+	if len(args) != 1 && len(args) != 2 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	domainName := mailfull.NormalizeDomainName(args[0])
+	selector := mailfull.DefaultDKIMSelector
+	if len(args) == 2 {
+		selector = args[1]
+	}
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	existKey, err := repo.DKIMKey(domainName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if existKey != nil && !force {
+		c.Meta.Errorf("DKIMKey: already exists (selector: %s), specify -f to replace\n", existKey.Selector())
+		return 1
+	}
+
+	key, err := mailfull.GenerateDKIMKey(selector, keyType, bits)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if err := repo.DKIMKeySet(domainName, key); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabases(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
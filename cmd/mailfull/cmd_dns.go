@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdDNS represents a CmdDNS.
+type CmdDNS struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdDNS) Synopsis() string {
+	return "Show recommended DNS records of a domain."
+}
+
+// Help returns long-form help text.
+func (c *CmdDNS) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s domain
+
+Description:
+    %s
+    The records are written in the zone file format, and include MX, SPF, DMARC,
+    DKIM (if the key exists), autoconfig, autodiscover and SRV records.
+    The records of the alias domains of the domain are also written.
+    The mail server is "mail_hostname" in the config. (or "myhostname" in the [postfix] section)
+
+Required Args:
+    domain
+        The domain name or the alias domain name.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdDNS) Run(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	domainName := mailfull.NormalizeDomainName(args[0])
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	domainNames := []string{domainName}
+
+	aliasDomains, err := repo.AliasDomains()
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	for _, aliasDomain := range aliasDomains {
+		if aliasDomain.Target() == domainName {
+			domainNames = append(domainNames, aliasDomain.Name())
+		}
+	}
+
+	for i, name := range domainNames {
+		records, err := repo.DNSRecords(name)
+		if err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+
+		if i > 0 {
+			fmt.Fprintf(c.UI.Writer, "\n")
+		}
+		fmt.Fprintf(c.UI.Writer, "; %s\n", mailfull.DomainNameToUnicode(name))
+
+		for _, record := range records {
+			fmt.Fprintf(c.UI.Writer, "%s\n", formatDNSRecord(record))
+		}
+	}
+
+	return 0
+}
+
+// formatDNSRecord returns the DNSRecord in the zone file format.
+func formatDNSRecord(record *mailfull.DNSRecord) string {
+	value := record.Value
+	if record.Type == "TXT" {
+		value = quoteTXT(value)
+	}
+
+	return fmt.Sprintf("%s.\tIN\t%s\t%s", record.Name, record.Type, value)
+}
+
+// quoteTXT returns the value of a TXT record as quoted strings of up to 255 characters.
+func quoteTXT(value string) string {
+	strs := []string{}
+
+	for len(value) > 255 {
+		strs = append(strs, `"`+value[:255]+`"`)
+		value = value[255:]
+	}
+	strs = append(strs, `"`+value+`"`)
+
+	if len(strs) == 1 {
+		return strs[0]
+	}
+
+	return "( " + strings.Join(strs, " ") + " )"
+}
@@ -29,6 +29,7 @@ Description:
 Required Args:
     domain
         The domain name that you want to create.
+        An internationalized domain name is stored in the ASCII form. (e.g. xn--r8jz45g.jp)
 
 Optional Args:
     -n
@@ -53,7 +54,7 @@ func (c *CmdDomainAdd) Run(args []string) int {
 		return 1
 	}
 
-	domainName := args[0]
+	domainName := mailfull.NormalizeDomainName(args[0])
 
 	repo, err := mailfull.OpenRepository(".")
 	if err != nil {
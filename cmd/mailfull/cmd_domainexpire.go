@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdDomainExpire represents a CmdDomainExpire.
+type CmdDomainExpire struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdDomainExpire) Synopsis() string {
+	return "Set the expiry of a domain."
+}
+
+// Help returns long-form help text.
+func (c *CmdDomainExpire) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] domain expiry
+
+Description:
+    %s
+    The expired domain is treated as disabled when databases are updated.
+
+Required Args:
+    domain
+        The domain name.
+    expiry
+        The date "YYYY-MM-DD" in the local time zone, or the time in RFC 3339.
+        "never" removes the expiry.
+
+Optional Args:
+    -n
+        Don't update databases.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdDomainExpire) Run(args []string) int {
+	noCommit, err := noCommitFlag(&args)
+	if err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	if len(args) != 2 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	domainName := mailfull.NormalizeDomainName(args[0])
+
+	expiresAt, err := expiryArg(args[1])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	domain, err := repo.Domain(domainName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if domain == nil {
+		c.Meta.Errorf("%v\n", mailfull.ErrDomainNotExist)
+		return 1
+	}
+
+	domain.SetExpiresAt(expiresAt)
+
+	if err := repo.DomainUpdate(domain); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabases(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdDomainMeta represents a CmdDomainMeta.
+type CmdDomainMeta struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdDomainMeta) Synopsis() string {
+	return "Show or set metadata of a domain."
+}
+
+// Help returns long-form help text.
+func (c *CmdDomainMeta) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s domain [key [value]]
+
+Description:
+    %s
+    All metadata are shown as "key=value" if the key is omitted.
+    The value of the key is shown if the value is omitted.
+    An empty value removes the key.
+    Common keys are "%s", "%s" and "%s".
+    "%s" and "%s" are set automatically.
+
+Required Args:
+    domain
+        The domain name.
+
+Optional Args:
+    key
+        The key of the metadata.
+    value
+        The value of the metadata.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis(),
+		mailfull.MetadataDisplayName, mailfull.MetadataPhone, mailfull.MetadataNotes,
+		mailfull.MetadataCreatedAt, mailfull.MetadataModifiedAt)
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdDomainMeta) Run(args []string) int {
+	if len(args) < 1 || len(args) > 3 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	domainName := mailfull.NormalizeDomainName(args[0])
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	domain, err := repo.Domain(domainName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if domain == nil {
+		c.Meta.Errorf("%v\n", mailfull.ErrDomainNotExist)
+		return 1
+	}
+
+	switch len(args) {
+	case 1:
+		for _, key := range domain.Metadata().Keys() {
+			fmt.Fprintf(c.UI.Writer, "%s=%s\n", key, domain.Metadata().Get(key))
+		}
+	case 2:
+		fmt.Fprintf(c.UI.Writer, "%s\n", domain.Metadata().Get(args[1]))
+	case 3:
+		if err := domain.Metadata().Set(args[1], args[2]); err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+
+		if err := repo.DomainUpdate(domain); err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+	}
+
+	return 0
+}
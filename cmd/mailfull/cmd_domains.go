@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
 	"sort"
 
@@ -22,11 +24,16 @@ func (c *CmdDomains) Synopsis() string {
 func (c *CmdDomains) Help() string {
 	txt := fmt.Sprintf(`
 Usage:
-    %s %s
+    %s %s [-l]
 
 Description:
     %s
     Disabled domains are marked "!" the beginning.
+    Internationalized domain names are shown in Unicode.
+
+Optional Args:
+    -l
+        Show metadata of each domain as tab-separated "key=value" columns.
 `,
 		c.CmdName, c.SubCmdName,
 		c.Synopsis())
@@ -36,6 +43,16 @@ Description:
 
 // Run runs the command and returns the exit status.
 func (c *CmdDomains) Run(args []string) int {
+	long := false
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.BoolVar(&long, "l", long, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
 	repo, err := mailfull.OpenRepository(".")
 	if err != nil {
 		c.Meta.Errorf("%v\n", err)
@@ -55,7 +72,12 @@ func (c *CmdDomains) Run(args []string) int {
 			disableStr = "!"
 		}
 
-		fmt.Fprintf(c.UI.Writer, "%s%s\n", disableStr, domain.Name())
+		metadataStr := ""
+		if long {
+			metadataStr = metadataColumns(domain.Metadata())
+		}
+
+		fmt.Fprintf(c.UI.Writer, "%s%s%s\n", disableStr, mailfull.DomainNameToUnicode(domain.Name()), metadataStr)
 	}
 
 	return 0
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdExpiring represents a CmdExpiring.
+type CmdExpiring struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdExpiring) Synopsis() string {
+	return "Show domains and users which expire soon."
+}
+
+// Help returns long-form help text.
+func (c *CmdExpiring) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-within duration]
+
+Description:
+    %s
+    Each line shows the expiry, the state ("expired" or "expiring") and the domain or the address,
+    sorted by the expiry.
+
+Optional Args:
+    -within
+        The duration from now, e.g. "14d" or "12h". (default: "14d")
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// expiringEntry represents a Domain or a User which expires.
+type expiringEntry struct {
+	expiresAt time.Time
+	name      string
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdExpiring) Run(args []string) int {
+	withinStr := "14d"
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.StringVar(&withinStr, "within", withinStr, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+	args = flagSet.Args()
+
+	if len(args) != 0 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	within, err := parseDays(withinStr)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	domains, err := repo.Domains()
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	now := time.Now()
+	until := now.Add(within)
+
+	entries := []expiringEntry{}
+
+	for _, domain := range domains {
+		if domain.Expired(until) {
+			entries = append(entries, expiringEntry{domain.ExpiresAt(), mailfull.DomainNameToUnicode(domain.Name())})
+		}
+
+		users, err := repo.Users(domain.Name())
+		if err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+
+		for _, user := range users {
+			if user.Expired(until) {
+				entries = append(entries, expiringEntry{user.ExpiresAt(), mailfull.AddressToUnicode(user.Name() + "@" + domain.Name())})
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].expiresAt.Before(entries[j].expiresAt) })
+
+	for _, entry := range entries {
+		stateStr := "expiring"
+		if !now.Before(entry.expiresAt) {
+			stateStr = "expired"
+		}
+
+		fmt.Fprintf(c.UI.Writer, "%s %s %s\n", entry.expiresAt.Local().Format(time.RFC3339), stateStr, entry.name)
+	}
+
+	return 0
+}
+
+// parseDays parses the input as a duration, which also accepts a number of days such as "14d".
+func parseDays(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(s)
+}
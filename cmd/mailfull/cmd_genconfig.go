@@ -1,7 +1,14 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"syscall"
 
 	"github.com/directorz/mailfull-go"
 	"github.com/directorz/mailfull-go/cmd"
@@ -14,22 +21,45 @@ type CmdGenConfig struct {
 
 // Synopsis returns a one-line synopsis.
 func (c *CmdGenConfig) Synopsis() string {
-	return "Write a Postfix or Dovecot configuration to stdout."
+	return "Write a Postfix or Dovecot configuration."
 }
 
 // Help returns long-form help text.
 func (c *CmdGenConfig) Help() string {
 	txt := fmt.Sprintf(`
 Usage:
-    %s %s name
+    %s %s [-template] [-profile profile] [-diff path | -w path] name
 
 Description:
     %s
+    Configurations are generated from the templates "main.cf.tmpl", "master.cf.tmpl",
+    "dovecot.conf.tmpl", "dovecot-2.3.conf.tmpl" and "dovecot-2.4.conf.tmpl".
+    Put a template into ".mailfull/templates/" to override the default template.
 
 Required Args:
     name
         The software name that you want to generate a configuration.
-        Available names are "postfix" and "dovecot".
+        Available names are:
+            postfix         main.cf
+            postfix-master  submission and smtps services of master.cf
+            dovecot         dovecot.conf
+
+Optional Args:
+    -template
+        Write the template in use instead of the configuration.
+    -profile
+        The profile of the Dovecot configuration.
+        Available profiles are "legacy", "2.3" and "2.4".
+        (default: the value of "profile" in the [dovecot] section of the config)
+    -diff
+        Write a unified diff from the file of the path instead of the configuration.
+        The "Generated by" line is ignored on comparing.
+        The exit status is 1 if there are differences, as diff(1) does.
+        It cannot be used with -w.
+    -w
+        Write the configuration to the file of the path instead of stdout.
+        The file is replaced atomically with the permission and the owner kept.
+        It cannot be used with "postfix-master", which is a part of master.cf.
 `,
 		c.CmdName, c.SubCmdName,
 		c.Synopsis())
@@ -39,6 +69,23 @@ Required Args:
 
 // Run runs the command and returns the exit status.
 func (c *CmdGenConfig) Run(args []string) int {
+	templateFlag := false
+	profile := ""
+	diffPath := ""
+	writePath := ""
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.BoolVar(&templateFlag, "template", templateFlag, "")
+	flagSet.StringVar(&profile, "profile", profile, "")
+	flagSet.StringVar(&diffPath, "diff", diffPath, "")
+	flagSet.StringVar(&writePath, "w", writePath, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+	args = flagSet.Args()
+
 	if len(args) != 1 {
 		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
 		return 1
@@ -46,23 +93,155 @@ func (c *CmdGenConfig) Run(args []string) int {
 
 	softwareName := args[0]
 
+	if diffPath != "" && writePath != "" {
+		c.Meta.Errorf("-diff and -w cannot be used together.\n")
+		return 1
+	}
+	if writePath != "" && softwareName == "postfix-master" {
+		c.Meta.Errorf("-w cannot be used with \"postfix-master\", append the services to master.cf.\n")
+		return 1
+	}
+
 	repo, err := mailfull.OpenRepository(".")
 	if err != nil {
 		c.Meta.Errorf("%v\n", err)
 		return 1
 	}
 
+	templateName := ""
+	var generate func() (string, error)
+
 	switch softwareName {
 	case "postfix":
-		fmt.Fprintf(c.UI.Writer, "%s", repo.GenerateConfigPostfix())
+		templateName = mailfull.FileNameTemplatePostfixMain
+		generate = repo.GenerateConfigPostfix
+
+	case "postfix-master":
+		templateName = mailfull.FileNameTemplatePostfixMaster
+		generate = repo.GenerateConfigPostfixMaster
 
 	case "dovecot":
-		fmt.Fprintf(c.UI.Writer, "%s", repo.GenerateConfigDovecot())
+		if profile == "" {
+			profile = repo.Dovecot.Profile
+		}
+		templateName, err = mailfull.DovecotConfigTemplate(profile)
+		if err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+		generate = func() (string, error) { return repo.GenerateConfigDovecot(profile) }
 
 	default:
-		c.Meta.Errorf("Specify \"postfix\" or \"dovecot\".\n")
+		c.Meta.Errorf("Specify \"postfix\", \"postfix-master\" or \"dovecot\".\n")
 		return 1
 	}
 
+	if templateFlag {
+		generate = func() (string, error) { return repo.ConfigTemplate(templateName) }
+	}
+
+	text, err := generate()
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if diffPath != "" {
+		current, err := ioutil.ReadFile(diffPath)
+		if err != nil && err.(*os.PathError).Err != syscall.ENOENT {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+
+		diff := unifiedDiff(diffPath, "(generated)", string(current), text, normalizeGeneratedBy)
+		fmt.Fprintf(c.UI.Writer, "%s", diff)
+
+		if diff != "" {
+			return 1
+		}
+		return 0
+	}
+
+	if writePath != "" {
+		if err := writeFileAtomic(writePath, []byte(text)); err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(c.UI.Writer, "%s", text)
+
 	return 0
 }
+
+// generatedByPattern matches the header line that contains the version and the date.
+var generatedByPattern = regexp.MustCompile(`^# Generated by mailfull .* on .*$`)
+
+// normalizeGeneratedBy removes the version and the date from the "Generated by" line.
+func normalizeGeneratedBy(line string) string {
+	if generatedByPattern.MatchString(line) {
+		return "# Generated by mailfull"
+	}
+
+	return line
+}
+
+// writeFileAtomic writes the data to a temporary file and renames it to the path.
+// The permission and the owner of the existing file are kept.
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	uid, gid := -1, -1
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		if err.(*os.PathError).Err != syscall.ENOENT {
+			return err
+		}
+	} else {
+		mode = fi.Mode().Perm()
+		if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+			uid, gid = int(st.Uid), int(st.Gid)
+		}
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".")
+	if err != nil {
+		return err
+	}
+	tmpPath := file.Name()
+
+	if err := writeAndClose(file, data, mode, uid, gid); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// writeAndClose writes the data to the file, sets the permission and the owner, and closes it.
+func writeAndClose(file *os.File, data []byte, mode os.FileMode, uid, gid int) error {
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	if err := file.Chmod(mode); err != nil {
+		return err
+	}
+	if uid != -1 {
+		if err := file.Chown(uid, gid); err != nil {
+			return err
+		}
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+	"github.com/directorz/mailfull-go/lookupd"
+)
+
+// CmdLookupd represents a CmdLookupd.
+type CmdLookupd struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdLookupd) Synopsis() string {
+	return "Answer Postfix lookup queries from the repository."
+}
+
+// Help returns long-form help text.
+func (c *CmdLookupd) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-socketmap address] [-tcp table=address]... [-ttl duration]
+
+Description:
+    %s
+    Changes of the repository are applied without "commit".
+    Available tables are "domains", "destinations", "maildirs", "localtable", "senderlogins" and "recipientaccess".
+    The "mailboxes" table resolves an address with an extension (e.g. user+tag@domain) to its mailbox.
+    When "lookupd_socketmap" is set in the config, "genconfig postfix" refers to this server.
+
+Optional Args:
+    -socketmap
+        The address to answer the socketmap protocol.
+        e.g. "inet:127.0.0.1:10027", "unix:/var/spool/postfix/private/mailfull"
+        (default: the value of "lookupd_socketmap" in the config)
+    -tcp
+        The table and the address to answer the tcp_table protocol.
+        e.g. "domains=127.0.0.1:10028"
+        This option can be specified multiple times.
+    -ttl
+        Reuse tables read from the repository for the duration. (default: "10s")
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdLookupd) Run(args []string) int {
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	socketmapAddress := repo.LookupdSocketmap
+	tcpTables := stringsFlag{}
+	ttl := lookupd.DefaultTTL
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.StringVar(&socketmapAddress, "socketmap", socketmapAddress, "")
+	flagSet.Var(&tcpTables, "tcp", "")
+	flagSet.DurationVar(&ttl, "ttl", ttl, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	if flagSet.NArg() != 0 || (socketmapAddress == "" && len(tcpTables) == 0) {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	srv := lookupd.NewServer(repo, ttl)
+
+	listeners := []net.Listener{}
+	closeAll := func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}
+	errCh := make(chan error, len(tcpTables)+1)
+
+	if socketmapAddress != "" {
+		l, err := listen(socketmapAddress)
+		if err != nil {
+			closeAll()
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+		listeners = append(listeners, l)
+
+		go func() { errCh <- srv.ServeSocketmap(l) }()
+	}
+
+	for _, tcpTable := range tcpTables {
+		words := strings.SplitN(tcpTable, "=", 2)
+		if len(words) != 2 {
+			closeAll()
+			fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+			return 1
+		}
+		tableName := words[0]
+
+		l, err := listen(words[1])
+		if err != nil {
+			closeAll()
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+		listeners = append(listeners, l)
+
+		go func() { errCh <- srv.ServeTCPTable(l, tableName) }()
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case <-sigCh:
+		closeAll()
+		return 0
+	case err := <-errCh:
+		closeAll()
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+}
+
+// stringsFlag is a flag.Value that can be specified multiple times.
+type stringsFlag []string
+
+// String returns the values joined by comma.
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set appends the value.
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdMasterUserAdd represents a CmdMasterUserAdd.
+type CmdMasterUserAdd struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdMasterUserAdd) Synopsis() string {
+	return "Create a new master user."
+}
+
+// Help returns long-form help text.
+func (c *CmdMasterUserAdd) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] name [password]
+
+Description:
+    %s
+    A master user can log in as any user with the password of the master user, by IMAP and POP3 only.
+    The login name is "address%sname". (e.g. "user@example.com%ssupport")
+    User names cannot contain "%s". Existing users with it are reported by the check command.
+
+Required Args:
+    name
+        The name of the master user.
+
+Optional Args:
+    -n
+        Don't update databases.
+    password
+        Specify the password instead of your typing.
+        This option is NOT recommended because the password will be visible in your shell history.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis(),
+		mailfull.MasterUserSeparator, mailfull.MasterUserSeparator, mailfull.MasterUserSeparator)
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdMasterUserAdd) Run(args []string) int {
+	noCommit, err := noCommitFlag(&args)
+	if err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	if len(args) != 1 && len(args) != 2 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	masterUserName := args[0]
+
+	rawPassword := ""
+	if len(args) == 2 {
+		rawPassword = args[1]
+	}
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if len(args) != 2 {
+		input1, err := c.UI.AskSecret(fmt.Sprintf("Enter new password for %s:", masterUserName))
+		if err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+		input2, err := c.UI.AskSecret("Retype new password:")
+		if err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+		if input1 != input2 {
+			c.Meta.Errorf("inputs do not match.\n")
+			return 1
+		}
+		rawPassword = input1
+	}
+
+	if err := repo.CheckPasswordPolicy(rawPassword); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	hashedPassword, err := mailfull.HashPassword(rawPassword)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	masterUser, err := mailfull.NewMasterUser(masterUserName, hashedPassword)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if err := repo.MasterUserCreate(masterUser); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabasePasswords(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdMasterUserDel represents a CmdMasterUserDel.
+type CmdMasterUserDel struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdMasterUserDel) Synopsis() string {
+	return "Delete a master user."
+}
+
+// Help returns long-form help text.
+func (c *CmdMasterUserDel) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] name
+
+Description:
+    %s
+
+Required Args:
+    name
+        The name of the master user that you want to delete.
+
+Optional Args:
+    -n
+        Don't update databases.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdMasterUserDel) Run(args []string) int {
+	noCommit, err := noCommitFlag(&args)
+	if err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	if len(args) != 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	masterUserName := args[0]
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if err := repo.MasterUserRemove(masterUserName); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabasePasswords(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
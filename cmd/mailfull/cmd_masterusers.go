@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdMasterUsers represents a CmdMasterUsers.
+type CmdMasterUsers struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdMasterUsers) Synopsis() string {
+	return "Show master users."
+}
+
+// Help returns long-form help text.
+func (c *CmdMasterUsers) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s
+
+Description:
+    %s
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdMasterUsers) Run(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	masterUsers, err := repo.MasterUsers()
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	sort.Slice(masterUsers, func(i, j int) bool { return masterUsers[i].Name() < masterUsers[j].Name() })
+
+	for _, masterUser := range masterUsers {
+		fmt.Fprintf(c.UI.Writer, "%s\n", masterUser.Name())
+	}
+
+	return 0
+}
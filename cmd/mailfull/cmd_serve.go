@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/api"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdServe represents a CmdServe.
+type CmdServe struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdServe) Synopsis() string {
+	return "Serve the repository as a JSON REST API."
+}
+
+// Help returns long-form help text.
+func (c *CmdServe) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-listen address]
+
+Description:
+    %s
+    Changes are not applied to databases until "POST /commit" is requested.
+    Every request requires "Authorization: Bearer <token>" created by "apitokenadd".
+    A domain-scoped token can only manage users, aliasusers and the catchall user inside its domain.
+
+Optional Args:
+    -listen
+        The address to listen on. (default: "127.0.0.1:8080")
+        Specify "unix:/path/to/socket" to listen on a Unix domain socket.
+
+Resources:
+    /domains                                GET, POST
+    /domains/{domain}                       GET, PUT, DELETE
+    /domains/{domain}/users                 GET, POST
+    /domains/{domain}/users/{user}          GET, PUT, DELETE
+    /domains/{domain}/aliasusers            GET, POST
+    /domains/{domain}/aliasusers/{user}     GET, PUT, DELETE
+    /domains/{domain}/catchall              GET, PUT, DELETE
+    /aliasdomains                           GET, POST
+    /aliasdomains/{aliasdomain}             GET, DELETE
+    /domains/{domain}/users/{user}/password POST (*)
+    /commit                                 POST
+
+    (*) Requested by the user with "current_password" and "new_password" instead of a token.
+        Too many failed attempts are rejected for a while.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdServe) Run(args []string) int {
+	listenAddress := "127.0.0.1:8080"
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.StringVar(&listenAddress, "listen", listenAddress, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	if flagSet.NArg() != 0 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	listener, err := listen(listenAddress)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	srv := &http.Server{
+		Handler: api.NewServer(repo),
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		srv.Close()
+	}()
+
+	if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdUserChangePw represents a CmdUserChangePw.
+type CmdUserChangePw struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdUserChangePw) Synopsis() string {
+	return "Change user's own password by entering the current password."
+}
+
+// Help returns long-form help text.
+func (c *CmdUserChangePw) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s address
+
+Description:
+    %s
+    The new password must satisfy the password policy.
+    The password database is updated immediately.
+
+Required Args:
+    address
+        The email address that you want to change the password.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdUserChangePw) Run(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	userName := address.LocalPart()
+	domainName := address.Domain()
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	currentPassword, err := c.UI.AskSecret(fmt.Sprintf("Enter current password for %s:", address))
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	input1, err := c.UI.AskSecret("Enter new password:")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	input2, err := c.UI.AskSecret("Retype new password:")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if input1 != input2 {
+		c.Meta.Errorf("inputs do not match.\n")
+		return 1
+	}
+
+	if err := repo.UserChangePassword(domainName, userName, currentPassword, input1); err != nil {
+		if err == mailfull.ErrPasswordMismatch {
+			// slow down guessing
+			time.Sleep(3 * time.Second)
+		}
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
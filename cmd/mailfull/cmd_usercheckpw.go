@@ -2,11 +2,9 @@ package main
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/directorz/mailfull-go"
 	"github.com/directorz/mailfull-go/cmd"
-	"github.com/jsimonetti/pwscheme/ssha"
 )
 
 // CmdUserCheckPw represents a CmdUserCheckPw.
@@ -50,15 +48,13 @@ func (c *CmdUserCheckPw) Run(args []string) int {
 		return 1
 	}
 
-	address := args[0]
-	words := strings.Split(address, "@")
-	if len(words) != 2 {
-		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
 		return 1
 	}
-
-	userName := words[0]
-	domainName := words[1]
+	userName := address.LocalPart()
+	domainName := address.Domain()
 
 	rawPassword := ""
 	if len(args) == 2 {
@@ -91,7 +87,7 @@ func (c *CmdUserCheckPw) Run(args []string) int {
 		rawPassword = input
 	}
 
-	if ok, _ := ssha.Validate(rawPassword, user.HashedPassword()); !ok {
+	if !user.ValidatePassword(rawPassword) {
 		fmt.Fprintf(c.UI.Writer, "The password you entered is incorrect.\n")
 		return 1
 	}
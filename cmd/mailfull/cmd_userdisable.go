@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdUserDisable represents a CmdUserDisable.
+type CmdUserDisable struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdUserDisable) Synopsis() string {
+	return "Disable a user temporarily."
+}
+
+// Help returns long-form help text.
+func (c *CmdUserDisable) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] [-reject] address
+
+Description:
+    %s
+    The disabled user cannot log in, but still receives mail unless -reject is specified.
+
+Required Args:
+    address
+        The email address that you want to disable.
+
+Optional Args:
+    -n
+        Don't update databases.
+    -reject
+        Reject incoming mail to the user.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdUserDisable) Run(args []string) int {
+	noCommit := false
+	reject := false
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.BoolVar(&noCommit, "n", noCommit, "")
+	flagSet.BoolVar(&reject, "reject", reject, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+	args = flagSet.Args()
+
+	if len(args) != 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	userName := address.LocalPart()
+	domainName := address.Domain()
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	user, err := repo.User(domainName, userName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if user == nil {
+		c.Meta.Errorf("%v\n", mailfull.ErrUserNotExist)
+		return 1
+	}
+
+	user.SetDisabled(true)
+	user.SetRejectsMail(reject)
+
+	if err := repo.UserUpdate(domainName, user); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabases(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdUserExpire represents a CmdUserExpire.
+type CmdUserExpire struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdUserExpire) Synopsis() string {
+	return "Set the expiry of a user."
+}
+
+// Help returns long-form help text.
+func (c *CmdUserExpire) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] address expiry
+
+Description:
+    %s
+    The expired user is treated as disabled when databases are updated.
+
+Required Args:
+    address
+        The email address.
+    expiry
+        The date "YYYY-MM-DD" in the local time zone, or the time in RFC 3339.
+        "never" removes the expiry.
+
+Optional Args:
+    -n
+        Don't update databases.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdUserExpire) Run(args []string) int {
+	noCommit, err := noCommitFlag(&args)
+	if err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	if len(args) != 2 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	userName := address.LocalPart()
+	domainName := address.Domain()
+
+	expiresAt, err := expiryArg(args[1])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	user, err := repo.User(domainName, userName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if user == nil {
+		c.Meta.Errorf("%v\n", mailfull.ErrUserNotExist)
+		return 1
+	}
+
+	user.SetExpiresAt(expiresAt)
+
+	if err := repo.UserUpdate(domainName, user); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabases(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
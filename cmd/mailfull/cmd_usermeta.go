@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdUserMeta represents a CmdUserMeta.
+type CmdUserMeta struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdUserMeta) Synopsis() string {
+	return "Show or set metadata of a user."
+}
+
+// Help returns long-form help text.
+func (c *CmdUserMeta) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s address [key [value]]
+
+Description:
+    %s
+    All metadata are shown as "key=value" if the key is omitted.
+    The value of the key is shown if the value is omitted.
+    An empty value removes the key.
+    Common keys are "%s", "%s" and "%s".
+    "%s" and "%s" are set automatically.
+
+Required Args:
+    address
+        The email address of the user.
+
+Optional Args:
+    key
+        The key of the metadata.
+    value
+        The value of the metadata.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis(),
+		mailfull.MetadataDisplayName, mailfull.MetadataPhone, mailfull.MetadataNotes,
+		mailfull.MetadataCreatedAt, mailfull.MetadataModifiedAt)
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdUserMeta) Run(args []string) int {
+	if len(args) < 1 || len(args) > 3 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	userName := address.LocalPart()
+	domainName := address.Domain()
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	user, err := repo.User(domainName, userName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if user == nil {
+		c.Meta.Errorf("%v\n", mailfull.ErrUserNotExist)
+		return 1
+	}
+
+	switch len(args) {
+	case 1:
+		for _, key := range user.Metadata().Keys() {
+			fmt.Fprintf(c.UI.Writer, "%s=%s\n", key, user.Metadata().Get(key))
+		}
+	case 2:
+		fmt.Fprintf(c.UI.Writer, "%s\n", user.Metadata().Get(args[1]))
+	case 3:
+		if err := user.Metadata().Set(args[1], args[2]); err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+
+		if err := repo.UserUpdate(domainName, user); err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -2,11 +2,9 @@ package main
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/directorz/mailfull-go"
 	"github.com/directorz/mailfull-go/cmd"
-	"github.com/jsimonetti/pwscheme/ssha"
 )
 
 // CmdUserPasswd represents a CmdUserPasswd.
@@ -58,15 +56,13 @@ func (c *CmdUserPasswd) Run(args []string) int {
 		return 1
 	}
 
-	address := args[0]
-	words := strings.Split(address, "@")
-	if len(words) != 2 {
-		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
 		return 1
 	}
-
-	userName := words[0]
-	domainName := words[1]
+	userName := address.LocalPart()
+	domainName := address.Domain()
 
 	rawPassword := ""
 	if len(args) == 2 {
@@ -107,14 +103,10 @@ func (c *CmdUserPasswd) Run(args []string) int {
 		rawPassword = input1
 	}
 
-	hashedPassword := mailfull.NeverMatchHashedPassword
-	if rawPassword != "" {
-		str, err := ssha.Generate(rawPassword, 4)
-		if err != nil {
-			c.Meta.Errorf("%v\n", err)
-			return 1
-		}
-		hashedPassword = str
+	hashedPassword, err := mailfull.HashPassword(rawPassword)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
 	}
 
 	user.SetHashedPassword(hashedPassword)
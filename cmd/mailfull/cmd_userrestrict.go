@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdUserRestrict represents a CmdUserRestrict.
+type CmdUserRestrict struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdUserRestrict) Synopsis() string {
+	return "Restrict protocols and networks of a user."
+}
+
+// Help returns long-form help text.
+func (c *CmdUserRestrict) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s [-n] [-nets networks] [-protocols protocols] address
+
+Description:
+    %s
+    Only the specified restrictions are changed. An empty value removes the restriction.
+    The restrictions are applied to Dovecot by the password database.
+
+Required Args:
+    address
+        The email address.
+
+Optional Args:
+    -n
+        Don't update databases.
+    -nets
+        Comma-separated IP addresses or CIDRs from which the user can log in.
+    -protocols
+        Comma-separated protocols which the user can use.
+        "%s", "%s", "%s" (SMTP AUTH) and "%s".
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis(),
+		mailfull.ProtocolIMAP, mailfull.ProtocolPOP3, mailfull.ProtocolSMTP, mailfull.ProtocolSubmission)
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdUserRestrict) Run(args []string) int {
+	noCommit := false
+	nets := ""
+	protocols := ""
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.BoolVar(&noCommit, "n", noCommit, "")
+	flagSet.StringVar(&nets, "nets", nets, "")
+	flagSet.StringVar(&protocols, "protocols", protocols, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+	args = flagSet.Args()
+
+	specified := map[string]bool{}
+	flagSet.Visit(func(f *flag.Flag) { specified[f.Name] = true })
+
+	if len(args) != 1 || (!specified["nets"] && !specified["protocols"]) {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	userName := address.LocalPart()
+	domainName := address.Domain()
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	user, err := repo.User(domainName, userName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if user == nil {
+		c.Meta.Errorf("%v\n", mailfull.ErrUserNotExist)
+		return 1
+	}
+
+	if specified["nets"] {
+		if err := user.SetAllowNets(splitList(nets)); err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+	}
+	if specified["protocols"] {
+		if err := user.SetProtocols(splitList(protocols)); err != nil {
+			c.Meta.Errorf("%v\n", err)
+			return 1
+		}
+	}
+
+	if err := repo.UserUpdate(domainName, user); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	if noCommit {
+		return 0
+	}
+	if err = repo.GenerateDatabases(); err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// splitList splits the comma-separated input into non-empty elements.
+func splitList(s string) []string {
+	list := []string{}
+
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/directorz/mailfull-go"
+	"github.com/directorz/mailfull-go/cmd"
+)
+
+// CmdUserRestrictions represents a CmdUserRestrictions.
+type CmdUserRestrictions struct {
+	cmd.Meta
+}
+
+// Synopsis returns a one-line synopsis.
+func (c *CmdUserRestrictions) Synopsis() string {
+	return "Show restrictions of a user."
+}
+
+// Help returns long-form help text.
+func (c *CmdUserRestrictions) Help() string {
+	txt := fmt.Sprintf(`
+Usage:
+    %s %s address
+
+Description:
+    %s
+
+Required Args:
+    address
+        The email address.
+`,
+		c.CmdName, c.SubCmdName,
+		c.Synopsis())
+
+	return txt[1:]
+}
+
+// Run runs the command and returns the exit status.
+func (c *CmdUserRestrictions) Run(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+
+	address, err := mailfull.ParseAddress(args[0])
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	userName := address.LocalPart()
+	domainName := address.Domain()
+
+	repo, err := mailfull.OpenRepository(".")
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+
+	user, err := repo.User(domainName, userName)
+	if err != nil {
+		c.Meta.Errorf("%v\n", err)
+		return 1
+	}
+	if user == nil {
+		c.Meta.Errorf("%v\n", mailfull.ErrUserNotExist)
+		return 1
+	}
+
+	nets := "any"
+	if len(user.AllowNets()) > 0 {
+		nets = strings.Join(user.AllowNets(), ",")
+	}
+	protocols := "all"
+	if len(user.Protocols()) > 0 {
+		protocols = strings.Join(user.Protocols(), ",")
+	}
+
+	fmt.Fprintf(c.UI.Writer, "nets: %s\n", nets)
+	fmt.Fprintf(c.UI.Writer, "protocols: %s\n", protocols)
+
+	return 0
+}
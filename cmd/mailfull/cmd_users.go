@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
 	"sort"
 
@@ -22,7 +24,7 @@ func (c *CmdUsers) Synopsis() string {
 func (c *CmdUsers) Help() string {
 	txt := fmt.Sprintf(`
 Usage:
-    %s %s domain
+    %s %s [-l] [-s] domain
 
 Description:
     %s
@@ -30,6 +32,13 @@ Description:
 Required Args:
     domain
         The domain name.
+
+Optional Args:
+    -l
+        Show metadata of each user as tab-separated "key=value" columns.
+    -s
+        Show the state of each user as a tab-separated column following the name.
+        The state is "enabled", "disabled", or "rejecting" if the user is disabled and rejects mail.
 `,
 		c.CmdName, c.SubCmdName,
 		c.Synopsis())
@@ -39,12 +48,25 @@ Required Args:
 
 // Run runs the command and returns the exit status.
 func (c *CmdUsers) Run(args []string) int {
+	long := false
+	state := false
+
+	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
+	flagSet.SetOutput(&bytes.Buffer{})
+	flagSet.BoolVar(&long, "l", long, "")
+	flagSet.BoolVar(&state, "s", state, "")
+	if err := flagSet.Parse(args); err != nil {
+		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
+		return 1
+	}
+	args = flagSet.Args()
+
 	if len(args) != 1 {
 		fmt.Fprintf(c.UI.ErrorWriter, "%v\n", c.Help())
 		return 1
 	}
 
-	targetDomainName := args[0]
+	targetDomainName := mailfull.NormalizeDomainName(args[0])
 
 	repo, err := mailfull.OpenRepository(".")
 	if err != nil {
@@ -60,7 +82,24 @@ func (c *CmdUsers) Run(args []string) int {
 	sort.Slice(users, func(i, j int) bool { return users[i].Name() < users[j].Name() })
 
 	for _, user := range users {
-		fmt.Fprintf(c.UI.Writer, "%s\n", user.Name())
+		stateStr := ""
+		if state {
+			switch {
+			case user.RejectsMail():
+				stateStr = "\trejecting"
+			case user.Disabled():
+				stateStr = "\tdisabled"
+			default:
+				stateStr = "\tenabled"
+			}
+		}
+
+		metadataStr := ""
+		if long {
+			metadataStr = metadataColumns(user.Metadata())
+		}
+
+		fmt.Fprintf(c.UI.Writer, "%s%s%s\n", user.Name(), stateStr, metadataStr)
 	}
 
 	return 0
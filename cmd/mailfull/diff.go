@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around changes in a unified diff.
+const diffContext = 3
+
+// diffLine represents a line of an edit script.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff returns a unified diff from the text to the other text.
+// Lines are compared after being converted by the normalize function.
+// It returns an empty string if there are no differences.
+func unifiedDiff(fromName, toName, from, to string, normalize func(string) string) string {
+	script := diffScript(splitLines(from), splitLines(to), normalize)
+
+	// line numbers before each entry of the script
+	fromPos := make([]int, len(script)+1)
+	toPos := make([]int, len(script)+1)
+	for i, line := range script {
+		fromPos[i+1] = fromPos[i]
+		toPos[i+1] = toPos[i]
+		if line.op != '+' {
+			fromPos[i+1]++
+		}
+		if line.op != '-' {
+			toPos[i+1]++
+		}
+	}
+
+	buf := &bytes.Buffer{}
+
+	for i := 0; i < len(script); {
+		if script[i].op == ' ' {
+			i++
+			continue
+		}
+
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+
+		end := i
+		for {
+			for end < len(script) && script[end].op != ' ' {
+				end++
+			}
+
+			next := end
+			for next < len(script) && next <= end+2*diffContext && script[next].op == ' ' {
+				next++
+			}
+			if next < len(script) && next <= end+2*diffContext && script[next].op != ' ' {
+				end = next
+				continue
+			}
+
+			break
+		}
+
+		stop := end + diffContext
+		if stop > len(script) {
+			stop = len(script)
+		}
+
+		if buf.Len() == 0 {
+			fmt.Fprintf(buf, "--- %s\n", fromName)
+			fmt.Fprintf(buf, "+++ %s\n", toName)
+		}
+
+		fmt.Fprintf(buf, "@@ -%s +%s @@\n",
+			diffRange(fromPos[start], fromPos[stop]-fromPos[start]),
+			diffRange(toPos[start], toPos[stop]-toPos[start]))
+
+		for _, line := range script[start:stop] {
+			fmt.Fprintf(buf, "%c%s\n", line.op, line.text)
+		}
+
+		i = stop
+	}
+
+	return buf.String()
+}
+
+// diffScript returns an edit script from the lines to the other lines.
+// It is computed with the longest common subsequence.
+func diffScript(from, to []string, normalize func(string) string) []diffLine {
+	fromKeys := make([]string, len(from))
+	for i, line := range from {
+		fromKeys[i] = normalize(line)
+	}
+	toKeys := make([]string, len(to))
+	for i, line := range to {
+		toKeys[i] = normalize(line)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of from[i:] and to[j:]
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if fromKeys[i] == toKeys[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	script := []diffLine{}
+
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case fromKeys[i] == toKeys[j]:
+			script = append(script, diffLine{' ', from[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			script = append(script, diffLine{'-', from[i]})
+			i++
+		default:
+			script = append(script, diffLine{'+', to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		script = append(script, diffLine{'-', from[i]})
+	}
+	for ; j < len(to); j++ {
+		script = append(script, diffLine{'+', to[j]})
+	}
+
+	return script
+}
+
+// diffRange returns a range of a hunk header.
+// The start is the number of lines before the hunk.
+func diffRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits the text into lines without line feeds.
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// numberedLines returns the text of lines numbered from 1 to n,
+// with the lines of the map replaced.
+func numberedLines(n int, replace map[int]string) string {
+	lines := []string{}
+	for i := 1; i <= n; i++ {
+		line, ok := replace[i]
+		if !ok {
+			line = strconv.Itoa(i)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "empty",
+			from: "",
+			to:   "",
+			want: "",
+		},
+		{
+			name: "identical",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name: "generated by line is ignored",
+			from: "# Generated by mailfull v1.0.0 on 2026-01-01\na\n",
+			to:   "# Generated by mailfull v1.1.0 on 2026-10-19\na\n",
+			want: "",
+		},
+		{
+			name: "insert only",
+			from: "",
+			to:   "a\nb\n",
+			want: "--- from\n+++ to\n" +
+				"@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "delete only",
+			from: "a\nb\n",
+			to:   "",
+			want: "--- from\n+++ to\n" +
+				"@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "context",
+			from: numberedLines(10, nil),
+			to:   numberedLines(10, map[int]string{5: "x"}),
+			want: "--- from\n+++ to\n" +
+				"@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+x\n 6\n 7\n 8\n",
+		},
+		{
+			name: "hunks merged",
+			from: numberedLines(10, nil),
+			to:   numberedLines(10, map[int]string{2: "x", 8: "y"}),
+			want: "--- from\n+++ to\n" +
+				"@@ -1,10 +1,10 @@\n 1\n-2\n+x\n 3\n 4\n 5\n 6\n 7\n-8\n+y\n 9\n 10\n",
+		},
+		{
+			name: "hunks separated",
+			from: numberedLines(20, nil),
+			to:   numberedLines(20, map[int]string{2: "x", 18: "y"}),
+			want: "--- from\n+++ to\n" +
+				"@@ -1,5 +1,5 @@\n 1\n-2\n+x\n 3\n 4\n 5\n" +
+				"@@ -15,6 +15,6 @@\n 15\n 16\n 17\n-18\n+y\n 19\n 20\n",
+		},
+	}
+
+	for _, test := range tests {
+		got := unifiedDiff("from", "to", test.from, test.to, normalizeGeneratedBy)
+		if got != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, got, test.want)
+		}
+	}
+}
@@ -7,9 +7,12 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/directorz/mailfull-go"
 	"github.com/directorz/mailfull-go/cmd"
@@ -74,6 +77,18 @@ func main() {
 			meta.SubCmdName = c.Subcommand()
 			return &CmdDomainEnable{Meta: meta}, nil
 		},
+		"domainexpire": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdDomainExpire{Meta: meta}, nil
+		},
+		"domainmeta": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdDomainMeta{Meta: meta}, nil
+		},
+		"expiring": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdExpiring{Meta: meta}, nil
+		},
 		"aliasdomains": func() (cli.Command, error) {
 			meta.SubCmdName = c.Subcommand()
 			return &CmdAliasDomains{Meta: meta}, nil
@@ -98,6 +113,54 @@ func main() {
 			meta.SubCmdName = c.Subcommand()
 			return &CmdUserDel{Meta: meta}, nil
 		},
+		"userdisable": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdUserDisable{Meta: meta}, nil
+		},
+		"userenable": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdUserEnable{Meta: meta}, nil
+		},
+		"userexpire": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdUserExpire{Meta: meta}, nil
+		},
+		"usermeta": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdUserMeta{Meta: meta}, nil
+		},
+		"userrestrict": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdUserRestrict{Meta: meta}, nil
+		},
+		"userrestrictions": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdUserRestrictions{Meta: meta}, nil
+		},
+		"apppw add": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdAppPwAdd{Meta: meta}, nil
+		},
+		"apppw list": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdAppPwList{Meta: meta}, nil
+		},
+		"apppw revoke": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdAppPwRevoke{Meta: meta}, nil
+		},
+		"masterusers": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdMasterUsers{Meta: meta}, nil
+		},
+		"masteruseradd": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdMasterUserAdd{Meta: meta}, nil
+		},
+		"masteruserdel": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdMasterUserDel{Meta: meta}, nil
+		},
 		"userpasswd": func() (cli.Command, error) {
 			meta.SubCmdName = c.Subcommand()
 			return &CmdUserPasswd{Meta: meta}, nil
@@ -106,6 +169,26 @@ func main() {
 			meta.SubCmdName = c.Subcommand()
 			return &CmdUserCheckPw{Meta: meta}, nil
 		},
+		"userchangepw": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdUserChangePw{Meta: meta}, nil
+		},
+		"auth-helper": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdAuthHelper{Meta: meta}, nil
+		},
+		"dns": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdDNS{Meta: meta}, nil
+		},
+		"dkim keygen": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdDKIMKeygen{Meta: meta}, nil
+		},
+		"dkim dns": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdDKIMDNS{Meta: meta}, nil
+		},
 		"aliasusers": func() (cli.Command, error) {
 			meta.SubCmdName = c.Subcommand()
 			return &CmdAliasUsers{Meta: meta}, nil
@@ -122,6 +205,18 @@ func main() {
 			meta.SubCmdName = c.Subcommand()
 			return &CmdAliasUserDel{Meta: meta}, nil
 		},
+		"aliassenders add": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdAliasSendersAdd{Meta: meta}, nil
+		},
+		"aliassenders del": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdAliasSendersDel{Meta: meta}, nil
+		},
+		"aliassenders list": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdAliasSendersList{Meta: meta}, nil
+		},
 		"catchall": func() (cli.Command, error) {
 			meta.SubCmdName = c.Subcommand()
 			return &CmdCatchAll{Meta: meta}, nil
@@ -138,6 +233,30 @@ func main() {
 			meta.SubCmdName = c.Subcommand()
 			return &CmdCommit{Meta: meta}, nil
 		},
+		"check": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdCheck{Meta: meta}, nil
+		},
+		"serve": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdServe{Meta: meta}, nil
+		},
+		"lookupd": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdLookupd{Meta: meta}, nil
+		},
+		"apitokens": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdAPITokens{Meta: meta}, nil
+		},
+		"apitokenadd": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdAPITokenAdd{Meta: meta}, nil
+		},
+		"apitokendel": func() (cli.Command, error) {
+			meta.SubCmdName = c.Subcommand()
+			return &CmdAPITokenDel{Meta: meta}, nil
+		},
 	}
 
 	exitCode, err := c.Run()
@@ -161,3 +280,43 @@ func noCommitFlag(pargs *[]string) (bool, error) {
 
 	return nFlag, err
 }
+
+// expiryArg parses the input as an expiry argument.
+// "never" returns the zero time which means no expiry.
+func expiryArg(s string) (time.Time, error) {
+	if s == "never" {
+		return time.Time{}, nil
+	}
+
+	return mailfull.ParseExpiry(s)
+}
+
+// metadataColumns returns the Metadata as tab-separated "key=value" columns with a leading tab.
+func metadataColumns(metadata mailfull.Metadata) string {
+	columns := ""
+	for _, key := range metadata.Keys() {
+		columns += "\t" + key + "=" + metadata.Get(key)
+	}
+
+	return columns
+}
+
+// listen announces on the input address.
+// The address is "host:port" or "inet:host:port" for TCP,
+// or "unix:/path/to/socket" for a Unix domain socket.
+func listen(address string) (net.Listener, error) {
+	if !strings.HasPrefix(address, "unix:") {
+		return net.Listen("tcp", strings.TrimPrefix(address, "inet:"))
+	}
+
+	path := strings.TrimPrefix(address, "unix:")
+
+	// remove a stale socket
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return net.Listen("unix", path)
+}
@@ -2,22 +2,64 @@ package mailfull
 
 // Filenames that are contained in the Repository.
 const (
-	DirNameConfig  = ".mailfull"
-	FileNameConfig = "config"
-
-	FileNameDomainDisable = ".vdomaindisable"
-	FileNameAliasDomains  = ".valiasdomains"
-	FileNameUsersPassword = ".vpasswd"
-	FileNameUserForwards  = ".forward"
-	FileNameAliasUsers    = ".valiases"
-	FileNameCatchAllUser  = ".vcatchall"
-
-	FileNameDbDomains      = "domains"
-	FileNameDbDestinations = "destinations"
-	FileNameDbMaildirs     = "maildirs"
-	FileNameDbLocaltable   = "localtable"
-	FileNameDbForwards     = "forwards"
-	FileNameDbPasswords    = "vpasswd"
+	DirNameConfig       = ".mailfull"
+	FileNameConfig      = "config"
+	FileNameAPITokens   = "apitokens"
+	FileNameMasterUsers = "masterusers"
+
+	DirNameTemplates              = "templates"
+	FileNameTemplatePostfixMain   = "main.cf.tmpl"
+	FileNameTemplatePostfixMaster = "master.cf.tmpl"
+	FileNameTemplateDovecot       = "dovecot.conf.tmpl"
+	FileNameTemplateDovecot23     = "dovecot-2.3.conf.tmpl"
+	FileNameTemplateDovecot24     = "dovecot-2.4.conf.tmpl"
+	FileNameTemplateAutoconfig    = "autoconfig.xml.tmpl"
+	FileNameTemplateAutodiscover  = "autodiscover.xml.tmpl"
+
+	FileNameAutoconfig   = "mail/config-v1.1.xml"
+	FileNameAutodiscover = "autodiscover/autodiscover.xml"
+
+	FileNameDomainDisable    = ".vdomaindisable"
+	FileNameDomainExpiry     = ".vdomainexpiry"
+	FileNameDomainMetadata   = ".vdomainmeta"
+	FileNameAliasDomains     = ".valiasdomains"
+	FileNameUsersPassword    = ".vpasswd"
+	FileNameUserForwards     = ".forward"
+	FileNameUserDisable      = ".vuserdisable"
+	FileNameUserExpiry       = ".vuserexpiry"
+	FileNameUserRestrictions = ".vuserrestrictions"
+	FileNameUserAppPasswords = ".vapppasswords"
+	FileNameUserMetadata     = ".vusermeta"
+	FileNameAliasUsers       = ".valiases"
+	FileNameAliasSenders     = ".valiassenders"
+	FileNameCatchAllUser     = ".vcatchall"
+
+	FileNameDomainDKIMSelector = ".vdkimselector"
+	FileNameDomainDKIMKey      = ".vdkimkey"
+
+	FileNameDbDomains         = "domains"
+	FileNameDbDestinations    = "destinations"
+	FileNameDbMaildirs        = "maildirs"
+	FileNameDbLocaltable      = "localtable"
+	FileNameDbForwards        = "forwards"
+	FileNameDbPasswords       = "vpasswd"
+	FileNameDbPasswordsDeny   = "vpasswd.deny"
+	FileNameDbAppPasswords    = "vpasswd.app"
+	FileNameDbMasterUsers     = "vpasswd.master"
+	FileNameDbSenderLogins    = "senderlogins"
+	FileNameDbRecipientAccess = "recipientaccess"
+
+	FileNameDbDKIMKeyTable     = "dkim_keytable"
+	FileNameDbDKIMSigningTable = "dkim_signingtable"
+	FileNameDbDKIMSelectors    = "dkim_selectors.map"
+	FileNameDbDKIMPaths        = "dkim_paths.map"
+)
+
+// Profiles of the Dovecot configuration.
+const (
+	DovecotProfileLegacy = "legacy"
+	DovecotProfile23     = "2.3"
+	DovecotProfile24     = "2.4"
 )
 
 // NeverMatchHashedPassword is hash string that is never match with any password.
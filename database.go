@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 )
 
 // repoData represents a repoData.
@@ -27,11 +28,23 @@ func (r *Repository) repoData() (*repoData, error) {
 		return nil, err
 	}
 
+	// expired Domains and Users are treated as disabled
+	now := time.Now()
+
 	for _, domain := range domains {
+		if domain.Expired(now) {
+			domain.SetDisabled(true)
+		}
+
 		users, err := r.Users(domain.Name())
 		if err != nil {
 			return nil, err
 		}
+		for _, user := range users {
+			if user.Expired(now) {
+				user.SetDisabled(true)
+			}
+		}
 		domain.Users = users
 
 		aliasUsers, err := r.AliasUsers(domain.Name())
@@ -45,6 +58,12 @@ func (r *Repository) repoData() (*repoData, error) {
 			return nil, err
 		}
 		domain.CatchAllUser = catchAllUser
+
+		dkimKey, err := r.DKIMKey(domain.Name())
+		if err != nil {
+			return nil, err
+		}
+		domain.DKIMKey = dkimKey
 	}
 
 	rd := &repoData{
@@ -55,13 +74,8 @@ func (r *Repository) repoData() (*repoData, error) {
 	return rd, nil
 }
 
-// GenerateDatabases generates databases from the Repository.
-func (r *Repository) GenerateDatabases() error {
-	rd, err := r.repoData()
-	if err != nil {
-		return err
-	}
-
+// sortAll sorts each slice of the repoData by name.
+func (rd *repoData) sortAll() {
 	sort.Slice(rd.Domains, func(i, j int) bool { return rd.Domains[i].Name() < rd.Domains[j].Name() })
 	sort.Slice(rd.AliasDomains, func(i, j int) bool { return rd.AliasDomains[i].Name() < rd.AliasDomains[j].Name() })
 
@@ -69,6 +83,16 @@ func (r *Repository) GenerateDatabases() error {
 		sort.Slice(domain.Users, func(i, j int) bool { return domain.Users[i].Name() < domain.Users[j].Name() })
 		sort.Slice(domain.AliasUsers, func(i, j int) bool { return domain.AliasUsers[i].Name() < domain.AliasUsers[j].Name() })
 	}
+}
+
+// GenerateDatabases generates databases from the Repository.
+func (r *Repository) GenerateDatabases() error {
+	rd, err := r.repoData()
+	if err != nil {
+		return err
+	}
+
+	rd.sortAll()
 
 	// Generate files
 	if err := r.generateDbDomains(rd); err != nil {
@@ -83,12 +107,33 @@ func (r *Repository) GenerateDatabases() error {
 	if err := r.generateDbLocaltable(rd); err != nil {
 		return err
 	}
+	if err := r.generateDbSenderLogins(rd); err != nil {
+		return err
+	}
+	if err := r.generateDbRecipientAccess(rd); err != nil {
+		return err
+	}
 	if err := r.generateDbForwards(rd); err != nil {
 		return err
 	}
 	if err := r.generateDbPasswords(rd); err != nil {
 		return err
 	}
+	if err := r.generateDbPasswordsDeny(rd); err != nil {
+		return err
+	}
+	if err := r.generateDbAppPasswords(rd); err != nil {
+		return err
+	}
+	if err := r.generateDbMasterUsers(); err != nil {
+		return err
+	}
+	if err := r.generateDbDKIM(rd); err != nil {
+		return err
+	}
+	if err := r.generateAutoconfig(rd); err != nil {
+		return err
+	}
 
 	// Generate DBs
 	if err := exec.Command(r.CmdPostmap, filepath.Join(r.DirDatabasePath, FileNameDbDomains)).Run(); err != nil {
@@ -103,6 +148,12 @@ func (r *Repository) GenerateDatabases() error {
 	if err := exec.Command(r.CmdPostmap, filepath.Join(r.DirDatabasePath, FileNameDbLocaltable)).Run(); err != nil {
 		return err
 	}
+	if err := exec.Command(r.CmdPostmap, filepath.Join(r.DirDatabasePath, FileNameDbSenderLogins)).Run(); err != nil {
+		return err
+	}
+	if err := exec.Command(r.CmdPostmap, filepath.Join(r.DirDatabasePath, FileNameDbRecipientAccess)).Run(); err != nil {
+		return err
+	}
 	if err := exec.Command(r.CmdPostalias, filepath.Join(r.DirDatabasePath, FileNameDbForwards)).Run(); err != nil {
 		return err
 	}
@@ -110,44 +161,68 @@ func (r *Repository) GenerateDatabases() error {
 	return nil
 }
 
-func (r *Repository) generateDbDomains(rd *repoData) error {
-	dbDomains, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbDomains))
+// GenerateDatabasePasswords generates only the password database from the Repository.
+// It is enough to apply password changes because Dovecot reads the database directly.
+func (r *Repository) GenerateDatabasePasswords() error {
+	rd, err := r.repoData()
 	if err != nil {
 		return err
 	}
-	if err := dbDomains.Chown(r.uid, r.gid); err != nil {
+
+	rd.sortAll()
+
+	if err := r.generateDbPasswords(rd); err != nil {
+		return err
+	}
+	if err := r.generateDbPasswordsDeny(rd); err != nil {
+		return err
+	}
+	if err := r.generateDbAppPasswords(rd); err != nil {
 		return err
 	}
-	defer dbDomains.Close()
+
+	return r.generateDbMasterUsers()
+}
+
+// dbEntry represents an entry of a lookup table.
+type dbEntry struct {
+	key   string
+	value string
+}
+
+// writeDbEntries writes entries to the file as a lookup table.
+func writeDbEntries(file *os.File, entries []dbEntry) error {
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(file, "%s %s\n", entry.key, entry.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dbDomains returns entries of the domains table.
+func dbDomains(rd *repoData) []dbEntry {
+	entries := []dbEntry{}
 
 	for _, domain := range rd.Domains {
 		if domain.Disabled() {
 			continue
 		}
 
-		if _, err := fmt.Fprintf(dbDomains, "%s virtual\n", domain.Name()); err != nil {
-			return err
-		}
+		entries = append(entries, dbEntry{domain.Name(), "virtual"})
 	}
 
 	for _, aliasDomain := range rd.AliasDomains {
-		if _, err := fmt.Fprintf(dbDomains, "%s virtual\n", aliasDomain.Name()); err != nil {
-			return err
-		}
+		entries = append(entries, dbEntry{aliasDomain.Name(), "virtual"})
 	}
 
-	return nil
+	return entries
 }
 
-func (r *Repository) generateDbDestinations(rd *repoData) error {
-	dbDestinations, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbDestinations))
-	if err != nil {
-		return err
-	}
-	if err := dbDestinations.Chown(r.uid, r.gid); err != nil {
-		return err
-	}
-	defer dbDestinations.Close()
+// dbDestinations returns entries of the destinations table.
+func dbDestinations(rd *repoData) []dbEntry {
+	entries := []dbEntry{}
 
 	for _, domain := range rd.Domains {
 		if domain.Disabled() {
@@ -164,52 +239,52 @@ func (r *Repository) generateDbDestinations(rd *repoData) error {
 				userName = ""
 			}
 
-			if len(user.Forwards()) > 0 {
-				if _, err := fmt.Fprintf(dbDestinations, "%s@%s %s|%s\n", userName, domain.Name(), underscoredDomainName, user.Name()); err != nil {
-					return err
+			if user.RejectsMail() {
+				if userName == "" {
+					continue
 				}
-			} else {
-				if _, err := fmt.Fprintf(dbDestinations, "%s@%s %s@%s\n", userName, domain.Name(), user.Name(), domain.Name()); err != nil {
-					return err
+
+				// mapped to itself not to be matched by the CatchAllUser,
+				// and rejected by the recipientaccess table
+				entries = append(entries, dbEntry{user.Name() + "@" + domain.Name(), user.Name() + "@" + domain.Name()})
+				for _, aliasDomain := range rd.AliasDomains {
+					if aliasDomain.Target() == domain.Name() {
+						entries = append(entries, dbEntry{user.Name() + "@" + aliasDomain.Name(), user.Name() + "@" + domain.Name()})
+					}
 				}
+				continue
+			}
+
+			if len(user.Forwards()) > 0 {
+				entries = append(entries, dbEntry{userName + "@" + domain.Name(), underscoredDomainName + "|" + user.Name()})
+			} else {
+				entries = append(entries, dbEntry{userName + "@" + domain.Name(), user.Name() + "@" + domain.Name()})
 			}
 
 			for _, aliasDomain := range rd.AliasDomains {
 				if aliasDomain.Target() == domain.Name() {
-					if _, err := fmt.Fprintf(dbDestinations, "%s@%s %s@%s\n", userName, aliasDomain.Name(), user.Name(), domain.Name()); err != nil {
-						return err
-					}
+					entries = append(entries, dbEntry{userName + "@" + aliasDomain.Name(), user.Name() + "@" + domain.Name()})
 				}
 			}
 		}
 
 		for _, aliasUser := range domain.AliasUsers {
-			if _, err := fmt.Fprintf(dbDestinations, "%s@%s %s\n", aliasUser.Name(), domain.Name(), strings.Join(aliasUser.Targets(), ",")); err != nil {
-				return err
-			}
+			entries = append(entries, dbEntry{aliasUser.Name() + "@" + domain.Name(), strings.Join(aliasUser.Targets(), ",")})
 
 			for _, aliasDomain := range rd.AliasDomains {
 				if aliasDomain.Target() == domain.Name() {
-					if _, err := fmt.Fprintf(dbDestinations, "%s@%s %s@%s\n", aliasUser.Name(), aliasDomain.Name(), aliasUser.Name(), domain.Name()); err != nil {
-						return err
-					}
+					entries = append(entries, dbEntry{aliasUser.Name() + "@" + aliasDomain.Name(), aliasUser.Name() + "@" + domain.Name()})
 				}
 			}
 		}
 	}
 
-	return nil
+	return entries
 }
 
-func (r *Repository) generateDbMaildirs(rd *repoData) error {
-	dbMaildirs, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbMaildirs))
-	if err != nil {
-		return err
-	}
-	if err := dbMaildirs.Chown(r.uid, r.gid); err != nil {
-		return err
-	}
-	defer dbMaildirs.Close()
+// dbMaildirs returns entries of the maildirs table.
+func dbMaildirs(rd *repoData) []dbEntry {
+	entries := []dbEntry{}
 
 	for _, domain := range rd.Domains {
 		if domain.Disabled() {
@@ -217,24 +292,21 @@ func (r *Repository) generateDbMaildirs(rd *repoData) error {
 		}
 
 		for _, user := range domain.Users {
-			if _, err := fmt.Fprintf(dbMaildirs, "%s@%s %s/%s/Maildir/\n", user.Name(), domain.Name(), domain.Name(), user.Name()); err != nil {
-				return err
+			if user.RejectsMail() {
+				continue
 			}
+
+			entries = append(entries, dbEntry{user.Name() + "@" + domain.Name(), domain.Name() + "/" + user.Name() + "/Maildir/"})
 		}
 	}
 
-	return nil
+	return entries
 }
 
-func (r *Repository) generateDbLocaltable(rd *repoData) error {
-	dbLocaltable, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbLocaltable))
-	if err != nil {
-		return err
-	}
-	if err := dbLocaltable.Chown(r.uid, r.gid); err != nil {
-		return err
-	}
-	defer dbLocaltable.Close()
+// dbLocaltable returns entries of the localtable table.
+// The key of each entry is a regular expression.
+func dbLocaltable(rd *repoData) []dbEntry {
+	entries := []dbEntry{}
 
 	for _, domain := range rd.Domains {
 		if domain.Disabled() {
@@ -246,7 +318,238 @@ func (r *Repository) generateDbLocaltable(rd *repoData) error {
 		escapedDomainName = strings.Replace(escapedDomainName, `-`, `_`, -1)
 		escapedDomainName = strings.Replace(escapedDomainName, `.`, `\.`, -1)
 
-		if _, err := fmt.Fprintf(dbLocaltable, "/^%s\\|.*$/ local\n", escapedDomainName); err != nil {
+		entries = append(entries, dbEntry{`^` + escapedDomainName + `\|.*$`, "local"})
+	}
+
+	return entries
+}
+
+// dbSenderLogins returns entries of the senderlogins table.
+// Each address is mapped to the logins of the Users who can send as the address.
+// AliasUsers are mapped to the logins of their targets which are Users.
+func dbSenderLogins(rd *repoData) []dbEntry {
+	// address -> login, compared case-insensitively
+	logins := map[string]string{}
+	for _, domain := range rd.Domains {
+		if domain.Disabled() {
+			continue
+		}
+
+		for _, user := range domain.Users {
+			login := user.Name() + "@" + domain.Name()
+			logins[strings.ToLower(login)] = login
+
+			for _, aliasDomain := range rd.AliasDomains {
+				if aliasDomain.Target() == domain.Name() {
+					logins[strings.ToLower(user.Name()+"@"+aliasDomain.Name())] = login
+				}
+			}
+		}
+	}
+
+	entries := []dbEntry{}
+
+	for _, domain := range rd.Domains {
+		if domain.Disabled() {
+			continue
+		}
+
+		for _, user := range domain.Users {
+			login := user.Name() + "@" + domain.Name()
+			entries = append(entries, dbEntry{login, login})
+
+			for _, aliasDomain := range rd.AliasDomains {
+				if aliasDomain.Target() == domain.Name() {
+					entries = append(entries, dbEntry{user.Name() + "@" + aliasDomain.Name(), login})
+				}
+			}
+		}
+
+		for _, aliasUser := range domain.AliasUsers {
+			aliasLogins := []string{}
+			for _, target := range aliasUser.Targets() {
+				login, ok := logins[strings.ToLower(target)]
+				if ok && !hasString(aliasLogins, login) {
+					aliasLogins = append(aliasLogins, login)
+				}
+			}
+			for _, sender := range aliasUser.Senders() {
+				login, ok := logins[strings.ToLower(sender)]
+				if ok && !hasString(aliasLogins, login) {
+					aliasLogins = append(aliasLogins, login)
+				}
+			}
+			if len(aliasLogins) == 0 {
+				continue
+			}
+
+			entries = append(entries, dbEntry{aliasUser.Name() + "@" + domain.Name(), strings.Join(aliasLogins, ",")})
+
+			for _, aliasDomain := range rd.AliasDomains {
+				if aliasDomain.Target() == domain.Name() {
+					entries = append(entries, dbEntry{aliasUser.Name() + "@" + aliasDomain.Name(), strings.Join(aliasLogins, ",")})
+				}
+			}
+		}
+	}
+
+	return entries
+}
+
+// dbRecipientAccess returns entries of the recipientaccess table.
+// Mails to the Users who reject mail are rejected explicitly,
+// because the addresses would be matched by the CatchAllUser otherwise.
+func dbRecipientAccess(rd *repoData) []dbEntry {
+	entries := []dbEntry{}
+
+	for _, domain := range rd.Domains {
+		if domain.Disabled() {
+			continue
+		}
+
+		for _, user := range domain.Users {
+			if !user.RejectsMail() {
+				continue
+			}
+
+			entries = append(entries, dbEntry{user.Name() + "@" + domain.Name(), "REJECT"})
+
+			for _, aliasDomain := range rd.AliasDomains {
+				if aliasDomain.Target() == domain.Name() {
+					entries = append(entries, dbEntry{user.Name() + "@" + aliasDomain.Name(), "REJECT"})
+				}
+			}
+		}
+	}
+
+	return entries
+}
+
+// dkimDomain represents a domain name signed with the DKIMKey of a Domain.
+type dkimDomain struct {
+	name   string
+	domain *Domain
+}
+
+// dkimDomains returns domain names signed with DKIMKeys including AliasDomains.
+func dkimDomains(rd *repoData) []dkimDomain {
+	dkimDomains := []dkimDomain{}
+
+	for _, domain := range rd.Domains {
+		if domain.Disabled() || domain.DKIMKey == nil {
+			continue
+		}
+
+		dkimDomains = append(dkimDomains, dkimDomain{domain.Name(), domain})
+
+		for _, aliasDomain := range rd.AliasDomains {
+			if aliasDomain.Target() == domain.Name() {
+				dkimDomains = append(dkimDomains, dkimDomain{aliasDomain.Name(), domain})
+			}
+		}
+	}
+
+	sort.Slice(dkimDomains, func(i, j int) bool { return dkimDomains[i].name < dkimDomains[j].name })
+
+	return dkimDomains
+}
+
+// dbDKIMKeyTable returns entries of the KeyTable of OpenDKIM.
+func (r *Repository) dbDKIMKeyTable(rd *repoData) []dbEntry {
+	entries := []dbEntry{}
+
+	for _, dd := range dkimDomains(rd) {
+		key := dd.domain.DKIMKey
+		entries = append(entries, dbEntry{key.RecordName(dd.name), dd.name + ":" + key.Selector() + ":" + r.DKIMSigningKeyPath(dd.domain.Name())})
+	}
+
+	return entries
+}
+
+// dbDKIMSigningTable returns entries of the SigningTable of OpenDKIM.
+func dbDKIMSigningTable(rd *repoData) []dbEntry {
+	entries := []dbEntry{}
+
+	for _, dd := range dkimDomains(rd) {
+		entries = append(entries, dbEntry{dd.name, dd.domain.DKIMKey.RecordName(dd.name)})
+	}
+
+	return entries
+}
+
+// dbDKIMSelectors returns entries of the selector_map of rspamd.
+func dbDKIMSelectors(rd *repoData) []dbEntry {
+	entries := []dbEntry{}
+
+	for _, dd := range dkimDomains(rd) {
+		entries = append(entries, dbEntry{dd.name, dd.domain.DKIMKey.Selector()})
+	}
+
+	return entries
+}
+
+// dbDKIMPaths returns entries of the path_map of rspamd.
+func (r *Repository) dbDKIMPaths(rd *repoData) []dbEntry {
+	entries := []dbEntry{}
+
+	for _, dd := range dkimDomains(rd) {
+		entries = append(entries, dbEntry{dd.name, r.DKIMSigningKeyPath(dd.domain.Name())})
+	}
+
+	return entries
+}
+
+func (r *Repository) generateDbDomains(rd *repoData) error {
+	dbDomainsFile, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbDomains))
+	if err != nil {
+		return err
+	}
+	if err := dbDomainsFile.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer dbDomainsFile.Close()
+
+	return writeDbEntries(dbDomainsFile, dbDomains(rd))
+}
+
+func (r *Repository) generateDbDestinations(rd *repoData) error {
+	dbDestinationsFile, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbDestinations))
+	if err != nil {
+		return err
+	}
+	if err := dbDestinationsFile.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer dbDestinationsFile.Close()
+
+	return writeDbEntries(dbDestinationsFile, dbDestinations(rd))
+}
+
+func (r *Repository) generateDbMaildirs(rd *repoData) error {
+	dbMaildirsFile, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbMaildirs))
+	if err != nil {
+		return err
+	}
+	if err := dbMaildirsFile.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer dbMaildirsFile.Close()
+
+	return writeDbEntries(dbMaildirsFile, dbMaildirs(rd))
+}
+
+func (r *Repository) generateDbLocaltable(rd *repoData) error {
+	dbLocaltableFile, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbLocaltable))
+	if err != nil {
+		return err
+	}
+	if err := dbLocaltableFile.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer dbLocaltableFile.Close()
+
+	for _, entry := range dbLocaltable(rd) {
+		if _, err := fmt.Fprintf(dbLocaltableFile, "/%s/ %s\n", entry.key, entry.value); err != nil {
 			return err
 		}
 	}
@@ -254,6 +557,14 @@ func (r *Repository) generateDbLocaltable(rd *repoData) error {
 	return nil
 }
 
+func (r *Repository) generateDbSenderLogins(rd *repoData) error {
+	return r.writeDbFile(FileNameDbSenderLogins, dbSenderLogins(rd))
+}
+
+func (r *Repository) generateDbRecipientAccess(rd *repoData) error {
+	return r.writeDbFile(FileNameDbRecipientAccess, dbRecipientAccess(rd))
+}
+
 func (r *Repository) generateDbForwards(rd *repoData) error {
 	dbForwards, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbForwards))
 	if err != nil {
@@ -274,6 +585,10 @@ func (r *Repository) generateDbForwards(rd *repoData) error {
 		underscoredDomainName = strings.Replace(underscoredDomainName, `-`, `_`, -1)
 
 		for _, user := range domain.Users {
+			if user.RejectsMail() {
+				continue
+			}
+
 			if len(user.Forwards()) > 0 {
 				if _, err := fmt.Fprintf(dbForwards, "%s|%s:%s\n", underscoredDomainName, user.Name(), strings.Join(user.Forwards(), ",")); err != nil {
 					return err
@@ -310,11 +625,253 @@ func (r *Repository) generateDbPasswords(rd *repoData) error {
 		}
 
 		for _, user := range domain.Users {
-			if _, err := fmt.Fprintf(dbPasswords, "%s@%s:%s\n", user.Name(), domain.Name(), user.HashedPassword()); err != nil {
+			// disabled Users are denied by the deny list for all protocols
+			if user.Disabled() {
+				continue
+			}
+
+			// extra fields of Dovecot passwd-file
+			fields := []string{}
+			if len(user.AllowNets()) > 0 {
+				fields = append(fields, "allow_nets="+strings.Join(user.AllowNets(), ","))
+			}
+
+			if len(fields) > 0 {
+				// user:password:uid:gid:gecos:home:shell:extra_fields
+				if _, err := fmt.Fprintf(dbPasswords, "%s@%s:%s::::::%s\n", user.Name(), domain.Name(), user.HashedPassword(), strings.Join(fields, " ")); err != nil {
+					return err
+				}
+			} else {
+				if _, err := fmt.Fprintf(dbPasswords, "%s@%s:%s\n", user.Name(), domain.Name(), user.HashedPassword()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// generateDbPasswordsDeny generates a deny list for all services, and a deny list for each service of Dovecot.
+// The list for all services contains the disabled Users and the Users of the disabled Domains.
+// The list for each service contains the Users who cannot use the service.
+func (r *Repository) generateDbPasswordsDeny(rd *repoData) error {
+	dbPasswordsDeny, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbPasswordsDeny))
+	if err != nil {
+		return err
+	}
+	if err := dbPasswordsDeny.Chown(r.uid, r.gid); err != nil {
+		dbPasswordsDeny.Close()
+		return err
+	}
+
+	for _, domain := range rd.Domains {
+		for _, user := range domain.Users {
+			if !domain.Disabled() && !user.Disabled() {
+				continue
+			}
+
+			if _, err := fmt.Fprintf(dbPasswordsDeny, "%s@%s\n", user.Name(), domain.Name()); err != nil {
+				dbPasswordsDeny.Close()
+				return err
+			}
+		}
+	}
+
+	dbPasswordsDeny.Close()
+
+	services := append(append([]string{}, protocols...), loginServices...)
+
+	for _, service := range services {
+		dbPasswordsDeny, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbPasswordsDeny+"."+service))
+		if err != nil {
+			return err
+		}
+		if err := dbPasswordsDeny.Chown(r.uid, r.gid); err != nil {
+			dbPasswordsDeny.Close()
+			return err
+		}
+
+		for _, domain := range rd.Domains {
+			if domain.Disabled() {
+				continue
+			}
+
+			for _, user := range domain.Users {
+				if user.AllowsProtocol(service) {
+					continue
+				}
+
+				if _, err := fmt.Fprintf(dbPasswordsDeny, "%s@%s\n", user.Name(), domain.Name()); err != nil {
+					dbPasswordsDeny.Close()
+					return err
+				}
+			}
+		}
+
+		dbPasswordsDeny.Close()
+	}
+
+	// The internal services are never denied, but Dovecot opens the file of the service
+	// on every passdb lookup (e.g. "doveadm auth test"), and fails it if the file does not exist.
+	for _, service := range internalServices {
+		dbPasswordsDeny, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbPasswordsDeny+"."+service))
+		if err != nil {
+			return err
+		}
+		if err := dbPasswordsDeny.Chown(r.uid, r.gid); err != nil {
+			dbPasswordsDeny.Close()
+			return err
+		}
+
+		dbPasswordsDeny.Close()
+	}
+
+	return nil
+}
+
+// dbAppPasswordsPaths returns paths of the AppPasswords databases.
+// The n-th database contains the n-th AppPassword of each User,
+// because each database has only one password for a User.
+func (r *Repository) dbAppPasswordsPaths() []string {
+	paths := make([]string, 0, AppPasswordsMax)
+
+	for i := 0; i < AppPasswordsMax; i++ {
+		paths = append(paths, filepath.Join(r.DirDatabasePath, fmt.Sprintf("%s.%d", FileNameDbAppPasswords, i)))
+	}
+
+	return paths
+}
+
+func (r *Repository) generateDbAppPasswords(rd *repoData) error {
+	// entries of each database
+	lines := make([][]string, AppPasswordsMax)
+
+	for _, domain := range rd.Domains {
+		if domain.Disabled() {
+			continue
+		}
+
+		for _, user := range domain.Users {
+			if user.Disabled() {
+				continue
+			}
+
+			appPasswords, err := r.readAppPasswordsFile(domain.Name(), user.Name())
+			if err != nil {
+				return err
+			}
+
+			extraFields := ""
+			if len(user.AllowNets()) > 0 {
+				extraFields = "::::::allow_nets=" + strings.Join(user.AllowNets(), ",")
+			}
+
+			for i, appPassword := range appPasswords {
+				if i >= AppPasswordsMax {
+					break
+				}
+
+				lines[i] = append(lines[i], fmt.Sprintf("%s@%s:%s%s", user.Name(), domain.Name(), appPassword.HashedPassword(), extraFields))
+			}
+		}
+	}
+
+	for i, path := range r.dbAppPasswordsPaths() {
+		dbAppPasswords, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := dbAppPasswords.Chown(r.uid, r.gid); err != nil {
+			dbAppPasswords.Close()
+			return err
+		}
+
+		for _, line := range lines[i] {
+			if _, err := fmt.Fprintf(dbAppPasswords, "%s\n", line); err != nil {
+				dbAppPasswords.Close()
+				return err
+			}
+		}
+
+		dbAppPasswords.Close()
+	}
+
+	return nil
+}
+
+// generateDbMasterUsers generates a list of the MasterUsers for each service of Dovecot.
+// The lists of the services which MasterUsers cannot log in to are empty.
+func (r *Repository) generateDbMasterUsers() error {
+	masterUsers, err := r.MasterUsers()
+	if err != nil {
+		return err
+	}
+
+	services := append(append([]string{}, protocols...), loginServices...)
+
+	for _, service := range services {
+		dbMasterUsers, err := os.Create(filepath.Join(r.DirDatabasePath, FileNameDbMasterUsers+"."+service))
+		if err != nil {
+			return err
+		}
+		if err := dbMasterUsers.Chown(r.uid, r.gid); err != nil {
+			dbMasterUsers.Close()
+			return err
+		}
+
+		if !hasString(masterUserServices, service) {
+			dbMasterUsers.Close()
+			continue
+		}
+
+		for _, masterUser := range masterUsers {
+			if _, err := fmt.Fprintf(dbMasterUsers, "%s:%s\n", masterUser.Name(), masterUser.HashedPassword()); err != nil {
+				dbMasterUsers.Close()
 				return err
 			}
 		}
+
+		dbMasterUsers.Close()
+	}
+
+	return nil
+}
+
+func (r *Repository) generateDbDKIM(rd *repoData) error {
+	if err := r.generateDKIMKeys(rd); err != nil {
+		return err
+	}
+
+	tables := []struct {
+		fileName string
+		entries  []dbEntry
+	}{
+		{FileNameDbDKIMKeyTable, r.dbDKIMKeyTable(rd)},
+		{FileNameDbDKIMSigningTable, dbDKIMSigningTable(rd)},
+		{FileNameDbDKIMSelectors, dbDKIMSelectors(rd)},
+		{FileNameDbDKIMPaths, r.dbDKIMPaths(rd)},
+	}
+
+	for _, table := range tables {
+		if err := r.writeDbFile(table.fileName, table.entries); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
+
+// writeDbFile writes entries to the file in the database directory.
+func (r *Repository) writeDbFile(fileName string, entries []dbEntry) error {
+	file, err := os.Create(filepath.Join(r.DirDatabasePath, fileName))
+	if err != nil {
+		return err
+	}
+	if err := file.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return writeDbEntries(file, entries)
+}
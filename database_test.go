@@ -0,0 +1,86 @@
+package mailfull
+
+import (
+	"testing"
+)
+
+// newTestRepoData returns a repoData of "example.com" whose CatchAllUser is "bob",
+// with "alice" who rejects mail, and "example.net" as the AliasDomain of it.
+func newTestRepoData(t *testing.T) *repoData {
+	t.Helper()
+
+	domain, err := NewDomain("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		user, err := NewUser(name, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		domain.Users = append(domain.Users, user)
+	}
+	domain.Users[0].SetDisabled(true)
+	domain.Users[0].SetRejectsMail(true)
+
+	catchAllUser, err := NewCatchAllUser("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain.CatchAllUser = catchAllUser
+
+	aliasDomain, err := NewAliasDomain("example.net", "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &repoData{
+		Domains:      []*Domain{domain},
+		AliasDomains: []*AliasDomain{aliasDomain},
+	}
+}
+
+func TestDbDestinationsRejectsMailWithCatchAll(t *testing.T) {
+	destinations := lookupMap(dbDestinations(newTestRepoData(t)))
+
+	tests := []struct {
+		key   string
+		value string
+	}{
+		// the User who rejects mail is not matched by the CatchAllUser
+		{"alice@example.com", "alice@example.com"},
+		{"alice@example.net", "alice@example.com"},
+		{"@example.com", "bob@example.com"},
+		{"@example.net", "bob@example.com"},
+		{"carol@example.com", "carol@example.com"},
+	}
+
+	for _, test := range tests {
+		entry, ok := destinations[test.key]
+		if !ok {
+			t.Errorf("%s: not found", test.key)
+			continue
+		}
+		if entry.value != test.value {
+			t.Errorf("%s: got %q, want %q", test.key, entry.value, test.value)
+		}
+	}
+}
+
+func TestDbRecipientAccessRejectsMailWithCatchAll(t *testing.T) {
+	access := lookupMap(dbRecipientAccess(newTestRepoData(t)))
+
+	for _, key := range []string{"alice@example.com", "alice@example.net"} {
+		entry, ok := access[key]
+		if !ok || entry.value != "REJECT" {
+			t.Errorf("%s: got %q, want %q", key, entry.value, "REJECT")
+		}
+	}
+
+	for _, key := range []string{"bob@example.com", "carol@example.com", "@example.com"} {
+		if _, ok := access[key]; ok {
+			t.Errorf("%s: rejected", key)
+		}
+	}
+}
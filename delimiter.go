@@ -0,0 +1,30 @@
+package mailfull
+
+import (
+	"strings"
+)
+
+// localPartBase returns the local part without the extension after the recipient delimiter.
+// Each character of the RecipientDelimiter is a delimiter as Postfix does.
+func (c *RepositoryConfig) localPartBase(localPart string) string {
+	if c.RecipientDelimiter == "" {
+		return localPart
+	}
+
+	if i := strings.IndexAny(localPart, c.RecipientDelimiter); i > 0 {
+		return localPart[:i]
+	}
+
+	return localPart
+}
+
+// collidesByDelimiter returns true if one of the local parts is an extended address of the other.
+// e.g. "foo" and "foo-bar"
+// Postfix delivers mails to "foo-bar" to "foo" only if "foo-bar" does not exist.
+func (c *RepositoryConfig) collidesByDelimiter(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return false
+	}
+
+	return strings.EqualFold(c.localPartBase(a), b) || strings.EqualFold(a, c.localPartBase(b))
+}
@@ -0,0 +1,378 @@
+package mailfull
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// Types of DKIM keys.
+const (
+	DKIMKeyTypeRSA     = "rsa"
+	DKIMKeyTypeEd25519 = "ed25519"
+)
+
+// DefaultDKIMSelector is the selector used if it is not specified.
+const DefaultDKIMSelector = "default"
+
+// MinDKIMKeyBits is the minimum bits of a generated RSA key. (RFC 8301)
+const MinDKIMKeyBits = 2048
+
+// Errors for the DKIMKey.
+var (
+	ErrInvalidDKIMSelector    = errors.New("DKIMKey: selector incorrect format")
+	ErrInvalidDKIMKeyType     = errors.New("DKIMKey: key type incorrect")
+	ErrDKIMKeyBitsTooSmall    = errors.New("DKIMKey: bits too small")
+	ErrInvalidFormatDKIMKey   = errors.New("DKIMKey: key incorrect format")
+	ErrDKIMKeyNotExist        = errors.New("DKIMKey: not exist")
+	ErrInvalidFormatDKIMFiles = errors.New("DKIMKey: selector and key files must exist together")
+)
+
+// oidEd25519 is the algorithm identifier of Ed25519. (RFC 8410)
+var oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+// pkcs8 represents a private key in PKCS #8.
+// crypto/x509 of Go 1.10 does not support Ed25519 keys.
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// DKIMKey represents a DKIM signing key of a Domain.
+type DKIMKey struct {
+	selector      string
+	keyType       string
+	privateKeyPEM []byte
+	publicKey     []byte
+}
+
+// GenerateDKIMKey generates a new DKIMKey.
+// The bits is used only for the RSA key, and must be at least MinDKIMKeyBits.
+func GenerateDKIMKey(selector, keyType string, bits int) (*DKIMKey, error) {
+	var block *pem.Block
+
+	switch keyType {
+	case DKIMKeyTypeRSA:
+		if bits < MinDKIMKeyBits {
+			return nil, ErrDKIMKeyBitsTooSmall
+		}
+		privateKey, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}
+
+	case DKIMKeyTypeEd25519:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		der, err := marshalEd25519PrivateKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+
+	default:
+		return nil, ErrInvalidDKIMKeyType
+	}
+
+	return NewDKIMKey(selector, pem.EncodeToMemory(block))
+}
+
+// NewDKIMKey creates a new DKIMKey instance from the PEM encoded private key.
+func NewDKIMKey(selector string, privateKeyPEM []byte) (*DKIMKey, error) {
+	k := &DKIMKey{}
+
+	if err := k.setSelector(selector); err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, ErrInvalidFormatDKIMKey
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, ErrInvalidFormatDKIMKey
+		}
+		publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		k.keyType = DKIMKeyTypeRSA
+		k.publicKey = publicKey
+
+	case "PRIVATE KEY":
+		privateKey, err := parseEd25519PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, ErrInvalidFormatDKIMKey
+		}
+
+		k.keyType = DKIMKeyTypeEd25519
+		k.publicKey = privateKey.Public().(ed25519.PublicKey)
+
+	default:
+		return nil, ErrInvalidFormatDKIMKey
+	}
+
+	k.privateKeyPEM = privateKeyPEM
+
+	return k, nil
+}
+
+// marshalEd25519PrivateKey returns the Ed25519 private key in PKCS #8 DER. (RFC 8410)
+func marshalEd25519PrivateKey(privateKey ed25519.PrivateKey) ([]byte, error) {
+	seed, err := asn1.Marshal(privateKey.Seed())
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(pkcs8{
+		Algo:       pkix.AlgorithmIdentifier{Algorithm: oidEd25519},
+		PrivateKey: seed,
+	})
+}
+
+// parseEd25519PrivateKey parses the Ed25519 private key in PKCS #8 DER. (RFC 8410)
+func parseEd25519PrivateKey(der []byte) (ed25519.PrivateKey, error) {
+	privateKeyInfo := pkcs8{}
+	if rest, err := asn1.Unmarshal(der, &privateKeyInfo); err != nil || len(rest) != 0 {
+		return nil, ErrInvalidFormatDKIMKey
+	}
+	if !privateKeyInfo.Algo.Algorithm.Equal(oidEd25519) {
+		return nil, ErrInvalidFormatDKIMKey
+	}
+
+	var seed []byte
+	if rest, err := asn1.Unmarshal(privateKeyInfo.PrivateKey, &seed); err != nil || len(rest) != 0 {
+		return nil, ErrInvalidFormatDKIMKey
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, ErrInvalidFormatDKIMKey
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// setSelector sets the selector.
+func (k *DKIMKey) setSelector(selector string) error {
+	if !validDKIMSelector(selector) {
+		return ErrInvalidDKIMSelector
+	}
+
+	k.selector = selector
+
+	return nil
+}
+
+// Selector returns selector.
+func (k *DKIMKey) Selector() string {
+	return k.selector
+}
+
+// KeyType returns key type.
+func (k *DKIMKey) KeyType() string {
+	return k.keyType
+}
+
+// PrivateKeyPEM returns the PEM encoded private key.
+func (k *DKIMKey) PrivateKeyPEM() []byte {
+	return k.privateKeyPEM
+}
+
+// RecordName returns the DNS name of the TXT record for the input domain name.
+func (k *DKIMKey) RecordName(domainName string) string {
+	return k.selector + "._domainkey." + domainName
+}
+
+// TXTRecord returns the value of the TXT record.
+func (k *DKIMKey) TXTRecord() string {
+	return "v=DKIM1; k=" + k.keyType + "; p=" + base64.StdEncoding.EncodeToString(k.publicKey)
+}
+
+// validDKIMSelector returns true if the input is correct format.
+func validDKIMSelector(selector string) bool {
+	return regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9\-]*[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9\-]*[A-Za-z0-9])?)*$`).MatchString(selector)
+}
+
+// DKIMKey returns the DKIMKey of the input Domain.
+// It returns nil if the Domain has no DKIMKey.
+func (r *Repository) DKIMKey(domainName string) (*DKIMKey, error) {
+	existDomain, err := r.Domain(domainName)
+	if err != nil {
+		return nil, err
+	}
+	if existDomain == nil {
+		return nil, ErrDomainNotExist
+	}
+
+	selector, err := ioutil.ReadFile(filepath.Join(r.DirMailDataPath, domainName, FileNameDomainDKIMSelector))
+	if err != nil {
+		if err.(*os.PathError).Err != syscall.ENOENT {
+			return nil, err
+		}
+		selector = nil
+	}
+
+	privateKeyPEM, err := ioutil.ReadFile(r.DKIMKeyPath(domainName))
+	if err != nil {
+		if err.(*os.PathError).Err != syscall.ENOENT {
+			return nil, err
+		}
+		privateKeyPEM = nil
+	}
+
+	if selector == nil && privateKeyPEM == nil {
+		return nil, nil
+	}
+	if selector == nil || privateKeyPEM == nil {
+		return nil, ErrInvalidFormatDKIMFiles
+	}
+
+	return NewDKIMKey(strings.TrimSpace(string(selector)), privateKeyPEM)
+}
+
+// DKIMKeyPath returns the path of the private key file of the input Domain.
+func (r *Repository) DKIMKeyPath(domainName string) string {
+	return filepath.Join(r.DirMailDataPath, domainName, FileNameDomainDKIMKey)
+}
+
+// DKIMSigningKeyPath returns the path of the private key file read by signing daemons.
+// It is the copy in DirDKIMKeysPath if set, otherwise the file in the domain directory.
+func (r *Repository) DKIMSigningKeyPath(domainName string) string {
+	if r.DirDKIMKeysPath == "" {
+		return r.DKIMKeyPath(domainName)
+	}
+
+	return filepath.Join(r.DirDKIMKeysPath, domainName+dkimKeyFileSuffix)
+}
+
+// dkimKeyFileSuffix is the suffix of private key files in DirDKIMKeysPath.
+const dkimKeyFileSuffix = ".key"
+
+// generateDKIMKeys writes the private keys into DirDKIMKeysPath
+// readable by the group of DKIMKeyGroup, and removes keys of removed or disabled domains.
+func (r *Repository) generateDKIMKeys(rd *repoData) error {
+	if r.DirDKIMKeysPath == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(r.DirDKIMKeysPath, 0750); err != nil {
+		return err
+	}
+	if err := os.Chown(r.DirDKIMKeysPath, r.uid, r.dkimGID); err != nil {
+		return err
+	}
+
+	domainNames := map[string]bool{}
+	for _, dd := range dkimDomains(rd) {
+		domainName := dd.domain.Name()
+		if domainNames[domainName] {
+			continue
+		}
+		domainNames[domainName] = true
+
+		if err := r.writeDKIMSigningKey(domainName, dd.domain.DKIMKey); err != nil {
+			return err
+		}
+	}
+
+	fileInfos, err := ioutil.ReadDir(r.DirDKIMKeysPath)
+	if err != nil {
+		return err
+	}
+	for _, fileInfo := range fileInfos {
+		name := fileInfo.Name()
+		if fileInfo.IsDir() || !strings.HasSuffix(name, dkimKeyFileSuffix) {
+			continue
+		}
+		domainName := strings.TrimSuffix(name, dkimKeyFileSuffix)
+		if domainNames[domainName] || !validDomainName(domainName) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(r.DirDKIMKeysPath, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeDKIMSigningKey writes the private key of the input Domain into DirDKIMKeysPath.
+func (r *Repository) writeDKIMSigningKey(domainName string, key *DKIMKey) error {
+	privateKeyFile, err := os.OpenFile(r.DKIMSigningKeyPath(domainName), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	defer privateKeyFile.Close()
+
+	if err := privateKeyFile.Chown(r.uid, r.dkimGID); err != nil {
+		return err
+	}
+	if err := privateKeyFile.Chmod(0640); err != nil {
+		return err
+	}
+
+	_, err = privateKeyFile.Write(key.PrivateKeyPEM())
+	return err
+}
+
+// DKIMKeySet sets the DKIMKey to the input Domain.
+// It replaces the existing DKIMKey.
+func (r *Repository) DKIMKeySet(domainName string, key *DKIMKey) error {
+	existDomain, err := r.Domain(domainName)
+	if err != nil {
+		return err
+	}
+	if existDomain == nil {
+		return ErrDomainNotExist
+	}
+
+	privateKeyFile, err := os.OpenFile(r.DKIMKeyPath(domainName), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := privateKeyFile.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer privateKeyFile.Close()
+
+	if _, err := privateKeyFile.Write(key.PrivateKeyPEM()); err != nil {
+		return err
+	}
+
+	selectorFile, err := os.OpenFile(filepath.Join(r.DirMailDataPath, domainName, FileNameDomainDKIMSelector), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := selectorFile.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer selectorFile.Close()
+
+	if _, err := selectorFile.WriteString(key.Selector() + "\n"); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package mailfull
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors for the DNSRecord.
+var (
+	ErrMailHostnameNotSet = errors.New("DNSRecord: mail_hostname is not set in the config")
+)
+
+// DNSRecord represents a DNS resource record recommended for a Domain.
+type DNSRecord struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+// mailHostname returns the host name of the mail server.
+// It falls back to myhostname of Postfix if mail_hostname is not set.
+func (c *RepositoryConfig) mailHostname() string {
+	if c.MailHostname != "" {
+		return c.MailHostname
+	}
+
+	return c.Postfix.MyHostname
+}
+
+// DNSRecords returns DNSRecords recommended for the input Domain or AliasDomain.
+// An AliasDomain gets the DKIM record of the target Domain.
+func (r *Repository) DNSRecords(domainName string) ([]*DNSRecord, error) {
+	hostname := r.mailHostname()
+	if hostname == "" {
+		return nil, ErrMailHostnameNotSet
+	}
+
+	targetDomainName := domainName
+
+	aliasDomain, err := r.AliasDomain(domainName)
+	if err != nil {
+		return nil, err
+	}
+	if aliasDomain != nil {
+		targetDomainName = aliasDomain.Target()
+	}
+
+	dkimKey, err := r.DKIMKey(targetDomainName)
+	if err != nil {
+		return nil, err
+	}
+
+	host := hostname + "."
+
+	records := []*DNSRecord{
+		{domainName, "MX", "10 " + host},
+		{domainName, "TXT", "v=spf1 mx ~all"},
+		{"_dmarc." + domainName, "TXT", "v=DMARC1; p=none; rua=mailto:postmaster@" + domainName},
+	}
+
+	if dkimKey != nil {
+		records = append(records, &DNSRecord{dkimKey.RecordName(domainName), "TXT", dkimKey.TXTRecord()})
+	}
+
+	records = append(records,
+		&DNSRecord{"autoconfig." + domainName, "CNAME", host},
+		&DNSRecord{"autodiscover." + domainName, "CNAME", host},
+		&DNSRecord{"_autodiscover._tcp." + domainName, "SRV", "0 1 443 " + host},
+		&DNSRecord{"_submission._tcp." + domainName, "SRV", "0 1 587 " + host},
+		&DNSRecord{"_submissions._tcp." + domainName, "SRV", "0 1 465 " + host},
+	)
+
+	services := []struct {
+		protocol string
+		service  string
+		port     int
+	}{
+		{"imap", "_imap", 143},
+		{"imap", "_imaps", 993},
+		{"pop3", "_pop3", 110},
+		{"pop3", "_pop3s", 995},
+	}
+	for _, s := range services {
+		if hasString(r.Dovecot.Protocols, s.protocol) {
+			records = append(records, &DNSRecord{s.service + "._tcp." + domainName, "SRV", fmt.Sprintf("0 1 %d %s", s.port, host)})
+		}
+	}
+
+	return records, nil
+}
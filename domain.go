@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 )
@@ -12,26 +13,44 @@ import (
 type Domain struct {
 	name         string
 	disabled     bool
+	expiresAt    time.Time
+	metadata     Metadata
 	Users        []*User
 	AliasUsers   []*AliasUser
 	CatchAllUser *CatchAllUser
+	DKIMKey      *DKIMKey
 }
 
 // NewDomain creates a new Domain instance.
+// The name is normalized by NormalizeDomainName.
 func NewDomain(name string) (*Domain, error) {
+	name = NormalizeDomainName(name)
+	if err := validationError(ErrInvalidDomainName, domainNameRule(name)); err != nil {
+		return nil, err
+	}
+
+	return newDomain(name)
+}
+
+// newDomain creates a new Domain instance with the name as is.
+// It is used for the directories of existing Domains which may not be normalized,
+// or may be named before the rules of domain names.
+func newDomain(name string) (*Domain, error) {
 	d := &Domain{}
 
 	if err := d.setName(name); err != nil {
 		return nil, err
 	}
 
+	d.SetMetadata(Metadata{})
+
 	return d, nil
 }
 
 // setName sets the name.
 func (d *Domain) setName(name string) error {
-	if !validDomainName(name) {
-		return ErrInvalidDomainName
+	if err := validationError(ErrInvalidDomainName, existingDomainNameRule(name)); err != nil {
+		return err
 	}
 
 	d.name = name
@@ -39,6 +58,12 @@ func (d *Domain) setName(name string) error {
 	return nil
 }
 
+// NormalizeDomainName returns the domain name in lower case A-labels.
+// e.g. "Example.COM" -> "example.com", "例え.jp" -> "xn--r8jz45g.jp"
+func NormalizeDomainName(name string) string {
+	return strings.ToLower(DomainNameToASCII(name))
+}
+
 // Name returns name.
 func (d *Domain) Name() string {
 	return d.name
@@ -54,6 +79,31 @@ func (d *Domain) Disabled() bool {
 	return d.disabled
 }
 
+// SetExpiresAt sets the time when the Domain expires. The zero time means the Domain never expires.
+func (d *Domain) SetExpiresAt(expiresAt time.Time) {
+	d.expiresAt = expiresAt
+}
+
+// ExpiresAt returns the time when the Domain expires.
+func (d *Domain) ExpiresAt() time.Time {
+	return d.expiresAt
+}
+
+// Expired returns true if the Domain has expired at the input time.
+func (d *Domain) Expired(now time.Time) bool {
+	return expired(d.expiresAt, now)
+}
+
+// SetMetadata sets the Metadata.
+func (d *Domain) SetMetadata(metadata Metadata) {
+	d.metadata = metadata
+}
+
+// Metadata returns the Metadata. It can be changed by Metadata.Set.
+func (d *Domain) Metadata() Metadata {
+	return d.metadata
+}
+
 // Domains returns a Domain slice.
 func (r *Repository) Domains() ([]*Domain, error) {
 	fileInfos, err := ioutil.ReadDir(r.DirMailDataPath)
@@ -70,7 +120,7 @@ func (r *Repository) Domains() ([]*Domain, error) {
 
 		name := fileInfo.Name()
 
-		domain, err := NewDomain(name)
+		domain, err := newDomain(name)
 		if err != nil {
 			continue
 		}
@@ -81,6 +131,18 @@ func (r *Repository) Domains() ([]*Domain, error) {
 		}
 		domain.SetDisabled(disabled)
 
+		expiresAt, err := readExpiryFile(filepath.Join(r.DirMailDataPath, name, FileNameDomainExpiry), ErrInvalidFormatDomainExpiry)
+		if err != nil {
+			return nil, err
+		}
+		domain.SetExpiresAt(expiresAt)
+
+		metadata, err := readMetadataFile(filepath.Join(r.DirMailDataPath, name, FileNameDomainMetadata))
+		if err != nil {
+			return nil, err
+		}
+		domain.SetMetadata(metadata)
+
 		domains = append(domains, domain)
 	}
 
@@ -108,7 +170,7 @@ func (r *Repository) Domain(domainName string) (*Domain, error) {
 
 	name := domainName
 
-	domain, err := NewDomain(name)
+	domain, err := newDomain(name)
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +181,18 @@ func (r *Repository) Domain(domainName string) (*Domain, error) {
 	}
 	domain.SetDisabled(disabled)
 
+	expiresAt, err := readExpiryFile(filepath.Join(r.DirMailDataPath, name, FileNameDomainExpiry), ErrInvalidFormatDomainExpiry)
+	if err != nil {
+		return nil, err
+	}
+	domain.SetExpiresAt(expiresAt)
+
+	metadata, err := readMetadataFile(filepath.Join(r.DirMailDataPath, name, FileNameDomainMetadata))
+	if err != nil {
+		return nil, err
+	}
+	domain.SetMetadata(metadata)
+
 	return domain, nil
 }
 
@@ -161,6 +235,9 @@ func (r *Repository) DomainCreate(domain *Domain) error {
 	if existAliasDomain != nil {
 		return ErrAliasDomainAlreadyExist
 	}
+	if err := r.checkDomainNameCollision(domain.Name()); err != nil {
+		return err
+	}
 
 	domainDirPath := filepath.Join(r.DirMailDataPath, domain.Name())
 
@@ -204,6 +281,17 @@ func (r *Repository) DomainCreate(domain *Domain) error {
 		}
 	}
 
+	if err := r.writeExpiryFile(filepath.Join(domainDirPath, FileNameDomainExpiry), domain.ExpiresAt()); err != nil {
+		return err
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	domain.Metadata()[MetadataCreatedAt] = now
+	domain.Metadata()[MetadataModifiedAt] = now
+	if err := r.writeMetadataFile(filepath.Join(domainDirPath, FileNameDomainMetadata), domain.Metadata()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -221,6 +309,15 @@ func (r *Repository) DomainUpdate(domain *Domain) error {
 		return err
 	}
 
+	if err := r.writeExpiryFile(filepath.Join(r.DirMailDataPath, domain.Name(), FileNameDomainExpiry), domain.ExpiresAt()); err != nil {
+		return err
+	}
+
+	domain.Metadata()[MetadataModifiedAt] = time.Now().Format(time.RFC3339)
+	if err := r.writeMetadataFile(filepath.Join(r.DirMailDataPath, domain.Name(), FileNameDomainMetadata), domain.Metadata()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -251,6 +348,11 @@ func (r *Repository) DomainRemove(domainName string) error {
 		return err
 	}
 
+	// APITokens of the Domain must not be valid for a Domain created later with the same name
+	if err := r.apiTokensRemoveByScope(domainName); err != nil {
+		return err
+	}
+
 	return nil
 }
 
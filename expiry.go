@@ -0,0 +1,91 @@
+package mailfull
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Errors for the expiry.
+var (
+	ErrInvalidExpiry = errors.New("Expiry: incorrect format")
+)
+
+// expiryDateLayout is the layout of an expiry which is specified by the date only.
+const expiryDateLayout = "2006-01-02"
+
+// ParseExpiry parses the input as an expiry.
+// The input is a date "2006-01-02" which means the beginning of the day in the local time zone,
+// or a time in RFC 3339.
+func ParseExpiry(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(expiryDateLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, ErrInvalidExpiry
+	}
+
+	return t, nil
+}
+
+// expired returns true if the expiry is set and is not after now.
+func expired(expiresAt, now time.Time) bool {
+	return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
+
+// readExpiryFile returns the expiry written in the file.
+// It returns the zero time if the file does not exist.
+func readExpiryFile(path string, errInvalidFormat error) (time.Time, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if pathErr, ok := err.(*os.PathError); ok && pathErr.Err == syscall.ENOENT {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, errInvalidFormat
+	}
+
+	return t, nil
+}
+
+// writeExpiryFile writes the expiry to the file.
+// The file is removed if the expiry is the zero time.
+func (r *Repository) writeExpiryFile(path string, expiresAt time.Time) error {
+	if expiresAt.IsZero() {
+		if err := os.Remove(path); err != nil {
+			if err.(*os.PathError).Err == syscall.ENOENT {
+				return nil
+			}
+
+			return err
+		}
+
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := file.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "%s\n", expiresAt.Format(time.RFC3339)); err != nil {
+		return err
+	}
+
+	return nil
+}
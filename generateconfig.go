@@ -1,50 +1,61 @@
 package mailfull
 
 import (
-	"fmt"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
 	"time"
 )
 
-// GenerateConfigPostfix generate a configuration for Postfix.
-func (r *Repository) GenerateConfigPostfix() string {
-	cfg := fmt.Sprintf(`
-#
+// Errors for the configuration.
+var (
+	ErrConfigTemplateNotExist = errors.New("ConfigTemplate: not exist")
+	ErrInvalidDovecotProfile  = errors.New("ConfigTemplate: invalid dovecot profile")
+)
+
+// templatePostfixMain is the default template of main.cf.
+const templatePostfixMain = `#
 # Sample configuration: main.cf
-# Generated by mailfull %s on %s
+# Generated by mailfull {{.Version}} on {{.Date}}
 #
 
-#myhostname = host.example.com
+{{if .Postfix.MyHostname}}myhostname = {{.Postfix.MyHostname}}{{else}}#myhostname = host.example.com{{end}}
 mydomain = $myhostname
 myorigin = $mydomain
 
 inet_interfaces = all
 mydestination = $myhostname, localhost.$mydomain, localhost, $mydomain
 mynetworks_style = host
-recipient_delimiter = -
-message_size_limit = 10240000
-mailbox_size_limit = 51200000
-virtual_mailbox_limit = 51200000
-
-virtual_mailbox_domains = hash:%s
-virtual_mailbox_base = %s
-virtual_mailbox_maps = hash:%s
-virtual_uid_maps = static:%d
-virtual_gid_maps = static:%d
-virtual_alias_maps = hash:%s
-transport_maps = regexp:%s
-alias_maps = hash:/etc/aliases, hash:%s
-alias_database = hash:/etc/aliases, hash:%s
+{{if .RecipientDelimiter}}recipient_delimiter = {{.RecipientDelimiter}}{{else}}#recipient_delimiter = +{{end}}
+message_size_limit = {{.Postfix.MessageSizeLimit}}
+mailbox_size_limit = {{.Postfix.MailboxSizeLimit}}
+virtual_mailbox_limit = {{.Postfix.VirtualMailboxLimit}}
+
+virtual_mailbox_domains = {{.TableDomains}}
+virtual_mailbox_base = {{.DirMailDataPath}}
+virtual_mailbox_maps = {{.TableMaildirs}}
+virtual_uid_maps = static:{{.UID}}
+virtual_gid_maps = static:{{.GID}}
+virtual_alias_maps = {{.TableDestinations}}
+transport_maps = {{.TableLocaltable}}
+alias_maps = hash:/etc/aliases, hash:{{.PathForwards}}
+alias_database = hash:/etc/aliases, hash:{{.PathForwards}}
 
 smtpd_sasl_auth_enable = yes
 smtpd_sasl_local_domain = $myhostname
-smtpd_recipient_restrictions = permit_mynetworks, permit_sasl_authenticated, reject_unauth_destination
+smtpd_recipient_restrictions = check_recipient_access {{.TableRecipientAccess}}, {{join .Postfix.RecipientRestrictions ", "}}
+smtpd_sender_login_maps = {{.TableSenderLogins}}
 smtpd_sasl_type = dovecot
 smtpd_sasl_path = private/auth
 
-smtpd_tls_cert_file = /etc/pki/dovecot/certs/dovecot.pem
-smtpd_tls_key_file = /etc/pki/dovecot/private/dovecot.pem
-#smtpd_tls_CAfile =
+smtpd_tls_cert_file = {{.Postfix.TLSCertFile}}
+smtpd_tls_key_file = {{.Postfix.TLSKeyFile}}
+{{if .Postfix.TLSCAFile}}smtpd_tls_CAfile = {{.Postfix.TLSCAFile}}{{else}}#smtpd_tls_CAfile ={{end}}
 smtpd_tls_session_cache_database = btree:/var/lib/postfix/smtpd_scache
 smtpd_tls_mandatory_protocols = !SSLv2, !SSLv3
 
@@ -52,41 +63,63 @@ smtp_tls_security_level = may
 smtp_tls_loglevel = 1
 smtpd_tls_security_level = may
 smtpd_tls_loglevel = 1
-`,
-		Version, time.Now().Format(time.RFC3339),
-		filepath.Join(r.DirDatabasePath, FileNameDbDomains),
-		r.DirMailDataPath,
-		filepath.Join(r.DirDatabasePath, FileNameDbMaildirs),
-		r.uid,
-		r.gid,
-		filepath.Join(r.DirDatabasePath, FileNameDbDestinations),
-		filepath.Join(r.DirDatabasePath, FileNameDbLocaltable),
-		filepath.Join(r.DirDatabasePath, FileNameDbForwards),
-		filepath.Join(r.DirDatabasePath, FileNameDbForwards),
-	)
-
-	return cfg[1:]
-}
+`
 
-// GenerateConfigDovecot generate a configuration for Dovecot.
-func (r *Repository) GenerateConfigDovecot() string {
-	cfg := fmt.Sprintf(`
+// templatePostfixMaster is the default template of services in master.cf.
+const templatePostfixMaster = `#
+# Sample configuration: master.cf
+# Generated by mailfull {{.Version}} on {{.Date}}
+#
+# Append these services to master.cf.
+# SASL authentication is provided by Dovecot as configured in main.cf.
+# Dovecot sees these services as "submission", and port 25 as "smtp".
 #
+
+submission inet n       -       n       -       -       smtpd
+  -o syslog_name=postfix/submission
+  -o smtpd_tls_security_level=encrypt
+  -o smtpd_sasl_auth_enable=yes
+  -o smtpd_sasl_service=submission
+  -o smtpd_tls_auth_only=yes
+  -o smtpd_reject_unlisted_recipient=no
+  -o smtpd_client_restrictions={{join .Postfix.MUAClientRestrictions ","}}
+{{- if .Postfix.SenderRestrictions}}
+  -o smtpd_sender_restrictions={{join .Postfix.SenderRestrictions ","}}
+{{- end}}
+  -o smtpd_relay_restrictions=permit_sasl_authenticated,reject
+  -o milter_macro_daemon_name=ORIGINATING
+smtps     inet  n       -       n       -       -       smtpd
+  -o syslog_name=postfix/smtps
+  -o smtpd_tls_wrappermode=yes
+  -o smtpd_sasl_auth_enable=yes
+  -o smtpd_sasl_service=submission
+  -o smtpd_reject_unlisted_recipient=no
+  -o smtpd_client_restrictions={{join .Postfix.MUAClientRestrictions ","}}
+{{- if .Postfix.SenderRestrictions}}
+  -o smtpd_sender_restrictions={{join .Postfix.SenderRestrictions ","}}
+{{- end}}
+  -o smtpd_relay_restrictions=permit_sasl_authenticated,reject
+  -o milter_macro_daemon_name=ORIGINATING
+`
+
+// templateDovecot is the default template of dovecot.conf for the "legacy" profile.
+const templateDovecot = `#
 # Sample configuration: dovecot.conf
-# Generated by mailfull %s on %s
+# Generated by mailfull {{.Version}} on {{.Date}}
 #
 
-protocols = imap pop3
+protocols = {{join .Dovecot.Protocols " "}}
 auth_mechanisms = plain login
+auth_master_user_separator = {{.MasterUserSeparator}}
 mail_location = maildir:~/Maildir
 
 ssl = yes
-ssl_cert = </etc/pki/dovecot/certs/dovecot.pem
-ssl_key = </etc/pki/dovecot/private/dovecot.pem
-#ssl_ca =
+ssl_cert = <{{.Dovecot.TLSCertFile}}
+ssl_key = <{{.Dovecot.TLSKeyFile}}
+{{if .Dovecot.TLSCAFile}}ssl_ca = <{{.Dovecot.TLSCAFile}}{{else}}#ssl_ca ={{end}}
 
 disable_plaintext_auth = yes
-
+{{if has .Dovecot.Protocols "imap"}}
 service imap-login {
   inet_listener imap {
     port = 143
@@ -96,7 +129,7 @@ service imap-login {
     ssl = yes
   }
 }
-
+{{end}}{{if has .Dovecot.Protocols "pop3"}}
 service pop3-login {
   inet_listener pop3 {
     port = 110
@@ -106,7 +139,7 @@ service pop3-login {
     ssl = yes
   }
 }
-
+{{end}}
 service auth {
   unix_listener /var/spool/postfix/private/auth {
     mode = 0666
@@ -117,24 +150,453 @@ service auth {
 
 passdb {
   driver = passwd-file
-  args = %s
+  args = {{.PathMasterUsers}}.%s
+  master = yes
+  result_success = continue
 }
-userdb {
+passdb {
+  driver = passwd-file
+  args = {{.PathPasswordsDeny}}
+  deny = yes
+}
+passdb {
+  driver = passwd-file
+  args = {{.PathPasswordsDeny}}.%s
+  deny = yes
+}
+passdb {
+  driver = passwd-file
+  args = {{.PathPasswords}}
+}
+{{range .PathsAppPasswords}}passdb {
+  driver = passwd-file
+  args = {{.}}
+}
+{{end}}userdb {
   driver = static
-  args = uid=%d gid=%d home=%s/%%d/%%n
+  args = uid={{.UID}} gid={{.GID}} home={{.DirMailDataPath}}/%d/%n
+}
+{{if .Dovecot.PAMFallback}}
+passdb {
+  driver = pam
+}
+userdb {
+  driver = passwd
+}
+{{end}}`
+
+// templateDovecot23 is the default template of dovecot.conf for the "2.3" profile.
+const templateDovecot23 = `#
+# Sample configuration: dovecot.conf (Dovecot 2.3)
+# Generated by mailfull {{.Version}} on {{.Date}}
+#
+{{- if .Dovecot.LMTP}}
+# To deliver by LMTP, set "virtual_transport = lmtp:unix:private/dovecot-lmtp" in main.cf.
+#
+{{- end}}
+
+protocols = {{join .Dovecot.Protocols " "}}{{if .Dovecot.LMTP}} lmtp{{end}}
+auth_mechanisms = plain login
+auth_master_user_separator = {{.MasterUserSeparator}}
+mail_location = maildir:~/Maildir
+
+ssl = required
+ssl_cert = <{{.Dovecot.TLSCertFile}}
+ssl_key = <{{.Dovecot.TLSKeyFile}}
+{{if .Dovecot.TLSCAFile}}ssl_ca = <{{.Dovecot.TLSCAFile}}{{else}}#ssl_ca ={{end}}
+ssl_min_protocol = TLSv1.2
+
+disable_plaintext_auth = yes
+{{if has .Dovecot.Protocols "imap"}}
+service imap-login {
+  inet_listener imap {
+    port = 143
+  }
+  inet_listener imaps {
+    port = 993
+    ssl = yes
+  }
+}
+{{end}}{{if has .Dovecot.Protocols "pop3"}}
+service pop3-login {
+  inet_listener pop3 {
+    port = 110
+  }
+  inet_listener pop3s {
+    port = 995
+    ssl = yes
+  }
+}
+{{end}}{{if .Dovecot.LMTP}}
+service lmtp {
+  unix_listener /var/spool/postfix/private/dovecot-lmtp {
+    mode = 0600
+    user = postfix
+    group = postfix
+  }
+}
+{{if .RecipientDelimiter}}recipient_delimiter = {{.RecipientDelimiter}}
+{{end}}{{end}}
+service auth {
+  unix_listener /var/spool/postfix/private/auth {
+    mode = 0660
+    user = postfix
+    group = postfix
+  }
 }
 
+passdb {
+  driver = passwd-file
+  args = {{.PathMasterUsers}}.%s
+  master = yes
+  result_success = continue
+}
+passdb {
+  driver = passwd-file
+  args = {{.PathPasswordsDeny}}
+  deny = yes
+}
+passdb {
+  driver = passwd-file
+  args = {{.PathPasswordsDeny}}.%s
+  deny = yes
+}
+passdb {
+  driver = passwd-file
+  args = {{.PathPasswords}}
+}
+{{range .PathsAppPasswords}}passdb {
+  driver = passwd-file
+  args = {{.}}
+}
+{{end}}userdb {
+  driver = static
+  args = uid={{.UID}} gid={{.GID}} home={{.DirMailDataPath}}/%d/%n
+}
+{{if .Dovecot.PAMFallback}}
 passdb {
   driver = pam
 }
 userdb {
   driver = passwd
 }
-`,
-		Version, time.Now().Format(time.RFC3339),
-		filepath.Join(r.DirDatabasePath, FileNameDbPasswords),
-		r.uid, r.gid, r.DirMailDataPath,
-	)
+{{end}}{{if .Dovecot.Quota}}
+mail_plugins = $mail_plugins quota
+
+protocol imap {
+  mail_plugins = $mail_plugins imap_quota
+}
+
+plugin {
+  quota = maildir:User quota
+  quota_rule = *:storage={{.Dovecot.Quota}}
+}
+{{end}}{{if and .Dovecot.LMTP .Dovecot.Sieve}}
+protocol lmtp {
+  mail_plugins = $mail_plugins sieve
+}
+
+plugin {
+  sieve = file:~/sieve;active=~/.dovecot.sieve
+}
+{{end}}`
+
+// templateDovecot24 is the default template of dovecot.conf for the "2.4" profile.
+const templateDovecot24 = `#
+# Sample configuration: dovecot.conf (Dovecot 2.4)
+# Generated by mailfull {{.Version}} on {{.Date}}
+#
+{{- if .Dovecot.LMTP}}
+# To deliver by LMTP, set "virtual_transport = lmtp:unix:private/dovecot-lmtp" in main.cf.
+#
+{{- end}}
+
+dovecot_config_version = 2.4.0
+dovecot_storage_version = 2.4.0
+
+protocols = {{join .Dovecot.Protocols " "}}{{if .Dovecot.LMTP}} lmtp{{end}}
+auth_mechanisms = plain login
+auth_master_user_separator = {{.MasterUserSeparator}}
+auth_allow_cleartext = no
+
+mail_driver = maildir
+mail_path = ~/Maildir
+
+ssl = required
+ssl_server_cert_file = {{.Dovecot.TLSCertFile}}
+ssl_server_key_file = {{.Dovecot.TLSKeyFile}}
+{{if .Dovecot.TLSCAFile}}ssl_server_ca_file = {{.Dovecot.TLSCAFile}}{{else}}#ssl_server_ca_file ={{end}}
+ssl_min_protocol = TLSv1.2
+{{if has .Dovecot.Protocols "imap"}}
+service imap-login {
+  inet_listener imap {
+    port = 143
+  }
+  inet_listener imaps {
+    port = 993
+    ssl = yes
+  }
+}
+{{end}}{{if has .Dovecot.Protocols "pop3"}}
+service pop3-login {
+  inet_listener pop3 {
+    port = 110
+  }
+  inet_listener pop3s {
+    port = 995
+    ssl = yes
+  }
+}
+{{end}}{{if .Dovecot.LMTP}}
+service lmtp {
+  unix_listener /var/spool/postfix/private/dovecot-lmtp {
+    mode = 0600
+    user = postfix
+    group = postfix
+  }
+}
+{{if .RecipientDelimiter}}recipient_delimiter = {{.RecipientDelimiter}}
+{{end}}{{end}}
+service auth {
+  unix_listener /var/spool/postfix/private/auth {
+    mode = 0660
+    user = postfix
+    group = postfix
+  }
+}
+
+passdb master {
+  driver = passwd-file
+  passwd_file_path = {{.PathMasterUsers}}.%{protocol}
+  master = yes
+  result_success = continue
+}
+passdb deny {
+  driver = passwd-file
+  passwd_file_path = {{.PathPasswordsDeny}}
+  deny = yes
+}
+passdb deny_protocol {
+  driver = passwd-file
+  passwd_file_path = {{.PathPasswordsDeny}}.%{protocol}
+  deny = yes
+}
+passdb passwd-file {
+  passwd_file_path = {{.PathPasswords}}
+}
+{{range $i, $path := .PathsAppPasswords}}passdb apppassword{{$i}} {
+  driver = passwd-file
+  passwd_file_path = {{$path}}
+}
+{{end}}userdb static {
+  fields {
+    uid = {{.UID}}
+    gid = {{.GID}}
+    home = {{.DirMailDataPath}}/%{user | domain}/%{user | username}
+  }
+}
+{{if .Dovecot.PAMFallback}}
+passdb pam {
+}
+userdb passwd {
+}
+{{end}}{{if .Dovecot.Quota}}
+mail_plugins {
+  quota = yes
+}
+
+protocol imap {
+  mail_plugins {
+    imap_quota = yes
+  }
+}
+
+quota "User quota" {
+  storage_size = {{.Dovecot.Quota}}
+}
+{{end}}{{if and .Dovecot.LMTP .Dovecot.Sieve}}
+protocol lmtp {
+  mail_plugins {
+    sieve = yes
+  }
+}
+
+sieve_script personal {
+  driver = file
+  path = ~/sieve
+  active_path = ~/.dovecot.sieve
+}
+{{end}}`
+
+// defaultConfigTemplates is a map of template file names to the default templates.
+var defaultConfigTemplates = map[string]string{
+	FileNameTemplatePostfixMain:   templatePostfixMain,
+	FileNameTemplatePostfixMaster: templatePostfixMaster,
+	FileNameTemplateDovecot:       templateDovecot,
+	FileNameTemplateDovecot23:     templateDovecot23,
+	FileNameTemplateDovecot24:     templateDovecot24,
+	FileNameTemplateAutoconfig:    templateAutoconfig,
+	FileNameTemplateAutodiscover:  templateAutodiscover,
+}
+
+// configTemplateData is passed to templates of configurations.
+type configTemplateData struct {
+	*Repository
+
+	Version  string
+	Date     string
+	UID      int
+	GID      int
+	Hostname string
+	Domain   string
+
+	TableDomains         string
+	TableDestinations    string
+	TableMaildirs        string
+	TableLocaltable      string
+	TableSenderLogins    string
+	TableRecipientAccess string
+	PathForwards         string
+	PathPasswords        string
+	PathPasswordsDeny    string
+	PathsAppPasswords    []string
+	PathMasterUsers      string
+
+	MasterUserSeparator string
+}
+
+// configTemplateData returns a configTemplateData of the Repository.
+func (r *Repository) configTemplateData() *configTemplateData {
+	return &configTemplateData{
+		Repository: r,
+
+		Version:  Version,
+		Date:     time.Now().Format(time.RFC3339),
+		UID:      r.uid,
+		GID:      r.gid,
+		Hostname: r.mailHostname(),
+
+		TableDomains:         r.postfixTable("hash", FileNameDbDomains),
+		TableDestinations:    r.postfixTable("hash", FileNameDbDestinations),
+		TableMaildirs:        r.postfixTable("hash", FileNameDbMaildirs),
+		TableLocaltable:      r.postfixTable("regexp", FileNameDbLocaltable),
+		TableSenderLogins:    r.postfixTable("hash", FileNameDbSenderLogins),
+		TableRecipientAccess: r.postfixTable("hash", FileNameDbRecipientAccess),
+		PathForwards:         filepath.Join(r.DirDatabasePath, FileNameDbForwards),
+		PathPasswords:        filepath.Join(r.DirDatabasePath, FileNameDbPasswords),
+		PathPasswordsDeny:    filepath.Join(r.DirDatabasePath, FileNameDbPasswordsDeny),
+		PathsAppPasswords:    r.dbAppPasswordsPaths(),
+		PathMasterUsers:      filepath.Join(r.DirDatabasePath, FileNameDbMasterUsers),
+
+		MasterUserSeparator: MasterUserSeparator,
+	}
+}
+
+// ConfigTemplate returns a template of the input file name.
+// The file in the templates directory overrides the default template.
+func (r *Repository) ConfigTemplate(fileName string) (string, error) {
+	text, ok := defaultConfigTemplates[fileName]
+	if !ok {
+		return "", ErrConfigTemplateNotExist
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(r.DirConfigPath(), DirNameTemplates, fileName))
+	if err != nil {
+		if err.(*os.PathError).Err == syscall.ENOENT {
+			return text, nil
+		}
+
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// executeConfigTemplate executes a template of the input file name.
+func (r *Repository) executeConfigTemplate(fileName string, data *configTemplateData) (string, error) {
+	text, err := r.ConfigTemplate(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New(fileName).Funcs(template.FuncMap{
+		"join": strings.Join,
+		"has":  hasString,
+	}).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// GenerateConfigPostfix generate a configuration for Postfix.
+func (r *Repository) GenerateConfigPostfix() (string, error) {
+	return r.executeConfigTemplate(FileNameTemplatePostfixMain, r.configTemplateData())
+}
+
+// GenerateConfigPostfixMaster generate services of master.cf for Postfix.
+func (r *Repository) GenerateConfigPostfixMaster() (string, error) {
+	return r.executeConfigTemplate(FileNameTemplatePostfixMaster, r.configTemplateData())
+}
+
+// hasString returns true if the slice contains the string.
+func hasString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// postfixTable returns a Postfix lookup table of the database.
+// It refers to the lookupd socketmap if LookupdSocketmap is set.
+// A bare "host:port" is referred to as "inet:host:port", as lookupd listens on it.
+func (r *Repository) postfixTable(tableType, fileName string) string {
+	if r.LookupdSocketmap != "" {
+		address := r.LookupdSocketmap
+		if !strings.HasPrefix(address, "inet:") && !strings.HasPrefix(address, "unix:") {
+			address = "inet:" + address
+		}
+
+		return "socketmap:" + address + ":" + fileName
+	}
+
+	return tableType + ":" + filepath.Join(r.DirDatabasePath, fileName)
+}
+
+// DovecotConfigTemplate returns a template file name of the Dovecot profile.
+func DovecotConfigTemplate(profile string) (string, error) {
+	switch profile {
+	case DovecotProfileLegacy:
+		return FileNameTemplateDovecot, nil
+	case DovecotProfile23:
+		return FileNameTemplateDovecot23, nil
+	case DovecotProfile24:
+		return FileNameTemplateDovecot24, nil
+	}
+
+	return "", ErrInvalidDovecotProfile
+}
+
+// GenerateConfigDovecot generate a configuration for Dovecot.
+// If the profile is empty, the profile in the config is used.
+func (r *Repository) GenerateConfigDovecot(profile string) (string, error) {
+	if profile == "" {
+		profile = r.Dovecot.Profile
+	}
+
+	fileName, err := DovecotConfigTemplate(profile)
+	if err != nil {
+		return "", err
+	}
 
-	return cfg[1:]
+	return r.executeConfigTemplate(fileName, r.configTemplateData())
 }
@@ -0,0 +1,65 @@
+package mailfull
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// idnaACEPrefix is the prefix of an A-label.
+const idnaACEPrefix = "xn--"
+
+// DomainNameToASCII returns the domain name converted to A-labels by the IDNA Lookup profile. (UTS #46)
+// The name is also mapped to lower case and NFC.
+// e.g. "例え.jp" -> "xn--r8jz45g.jp"
+// It returns the input as is if the input cannot be converted.
+func DomainNameToASCII(name string) string {
+	ascii, err := idna.Lookup.ToASCII(name)
+	if err != nil {
+		return name
+	}
+
+	return ascii
+}
+
+// DomainNameToUnicode returns the domain name whose A-labels are converted to U-labels.
+// e.g. "xn--r8jz45g.jp" -> "例え.jp"
+// It returns the input as is if any of the A-labels is not valid.
+func DomainNameToUnicode(name string) string {
+	unicode, err := idna.Lookup.ToUnicode(name)
+	if err != nil {
+		return name
+	}
+
+	return unicode
+}
+
+// AddressToUnicode returns the email address whose domain part is converted by DomainNameToUnicode.
+func AddressToUnicode(address string) string {
+	i := strings.LastIndex(address, "@")
+	if i < 0 {
+		return address
+	}
+
+	return address[:i+1] + DomainNameToUnicode(address[i+1:])
+}
+
+// validALabel returns true if the label is not an A-label, or is an A-label of a valid U-label.
+func validALabel(label string) bool {
+	if !strings.HasPrefix(strings.ToLower(label), idnaACEPrefix) {
+		return true
+	}
+
+	unicode, err := idna.Lookup.ToUnicode(label)
+	if err != nil {
+		return false
+	}
+
+	// the A-label must be the one encoded from the U-label
+	ascii, err := idna.Lookup.ToASCII(unicode)
+	if err != nil {
+		return false
+	}
+
+	return ascii == strings.ToLower(label)
+}
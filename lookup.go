@@ -0,0 +1,149 @@
+package mailfull
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// LookupTableMailboxes is the name of the table which resolves an address to the address of its mailbox.
+// It is not generated as a database because Postfix strips the extension by itself.
+const LookupTableMailboxes = "mailboxes"
+
+// Errors for the lookup.
+var (
+	ErrLookupTableNotExist = errors.New("LookupTable: not exist")
+)
+
+// LookupTable represents lookup tables in memory.
+// It answers queries with the same entries as the generated databases.
+type LookupTable struct {
+	// table name -> key in lower case -> entry
+	tables     map[string]map[string]dbEntry
+	localtable []*regexp.Regexp
+	localValue []string
+
+	// alias domain name in lower case -> target domain name
+	aliasDomains map[string]string
+	config       *RepositoryConfig
+}
+
+// LookupTable returns a LookupTable of the current Repository.
+func (r *Repository) LookupTable() (*LookupTable, error) {
+	rd, err := r.repoData()
+	if err != nil {
+		return nil, err
+	}
+
+	rd.sortAll()
+
+	lt := &LookupTable{
+		tables: map[string]map[string]dbEntry{
+			FileNameDbDomains:         lookupMap(dbDomains(rd)),
+			FileNameDbDestinations:    lookupMap(dbDestinations(rd)),
+			FileNameDbMaildirs:        lookupMap(dbMaildirs(rd)),
+			FileNameDbSenderLogins:    lookupMap(dbSenderLogins(rd)),
+			FileNameDbRecipientAccess: lookupMap(dbRecipientAccess(rd)),
+		},
+		aliasDomains: map[string]string{},
+		config:       r.RepositoryConfig,
+	}
+
+	for _, aliasDomain := range rd.AliasDomains {
+		lt.aliasDomains[strings.ToLower(aliasDomain.Name())] = aliasDomain.Target()
+	}
+
+	for _, entry := range dbLocaltable(rd) {
+		re, err := regexp.Compile(`(?i)` + entry.key)
+		if err != nil {
+			return nil, err
+		}
+
+		lt.localtable = append(lt.localtable, re)
+		lt.localValue = append(lt.localValue, entry.value)
+	}
+
+	return lt, nil
+}
+
+// Lookup returns a value of the key in the table of the input name.
+// The table name is one of "domains", "destinations", "maildirs", "localtable", "senderlogins", "recipientaccess" and "mailboxes".
+// Keys are compared case-insensitively as Postfix does.
+func (lt *LookupTable) Lookup(tableName, key string) (string, bool, error) {
+	if tableName == LookupTableMailboxes {
+		mailbox, ok := lt.ResolveMailbox(key)
+		return mailbox, ok, nil
+	}
+
+	if tableName == FileNameDbLocaltable {
+		for i, re := range lt.localtable {
+			if re.MatchString(key) {
+				return lt.localValue[i], true, nil
+			}
+		}
+
+		return "", false, nil
+	}
+
+	entries, ok := lt.tables[tableName]
+	if !ok {
+		return "", false, ErrLookupTableNotExist
+	}
+
+	entry, ok := entries[strings.ToLower(key)]
+	if !ok {
+		return "", false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+// lookupMap returns a map of the entries keyed by the key in lower case.
+// The first entry wins if keys are duplicated as the first line of a database does.
+func lookupMap(entries []dbEntry) map[string]dbEntry {
+	m := make(map[string]dbEntry, len(entries))
+
+	for _, entry := range entries {
+		key := strings.ToLower(entry.key)
+		if _, ok := m[key]; ok {
+			continue
+		}
+		m[key] = entry
+	}
+
+	return m
+}
+
+// ResolveMailbox returns the address of the User whose mailbox receives mails to the input address.
+// An extension after the recipient delimiter is ignored, and an AliasDomain is replaced with its target.
+// e.g. "user+tag@example.com" -> "user@example.com"
+// AliasUsers are not resolved because they may have several targets.
+func (lt *LookupTable) ResolveMailbox(address string) (string, bool) {
+	i := strings.LastIndex(address, "@")
+	if i < 0 {
+		return "", false
+	}
+	localPart, domainName := address[:i], address[i+1:]
+
+	if target, ok := lt.aliasDomains[strings.ToLower(domainName)]; ok {
+		domainName = target
+	}
+
+	candidates := []string{localPart + "@" + domainName}
+	if base := lt.config.localPartBase(localPart); base != localPart {
+		candidates = append(candidates, base+"@"+domainName)
+	}
+
+	for _, candidate := range candidates {
+		if entry, ok := lt.tables[FileNameDbMaildirs][strings.ToLower(candidate)]; ok {
+			return entry.key, true
+		}
+
+		// an existing AliasUser is not an extended address of a User
+		if _, ok := lt.tables[FileNameDbDestinations][strings.ToLower(candidate)]; ok {
+			return "", false
+		}
+	}
+
+	return "", false
+}
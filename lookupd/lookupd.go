@@ -0,0 +1,79 @@
+/*
+Package lookupd provides servers that answer Postfix lookup queries from a mailfull repository.
+*/
+package lookupd
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// DefaultTTL is the default duration to reuse lookup tables read from the Repository.
+// Reading the Repository for each query does not scale because Postfix sends many queries.
+const DefaultTTL = 10 * time.Second
+
+// Server represents a Server.
+type Server struct {
+	repo *mailfull.Repository
+	ttl  time.Duration
+
+	mu       sync.Mutex
+	table    *mailfull.LookupTable
+	loadedAt time.Time
+}
+
+// NewServer creates a new Server instance.
+// Lookup tables are reloaded from the Repository when they are older than the ttl.
+// A ttl of 0 or less means DefaultTTL.
+func NewServer(repo *mailfull.Repository, ttl time.Duration) *Server {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	s := &Server{
+		repo: repo,
+		ttl:  ttl,
+	}
+
+	return s
+}
+
+// lookup returns a value of the key in the table of the input name.
+func (s *Server) lookup(tableName, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.table == nil || time.Since(s.loadedAt) >= s.ttl {
+		table, err := s.repo.LookupTable()
+		if err != nil {
+			return "", false, err
+		}
+
+		s.table = table
+		s.loadedAt = time.Now()
+	}
+
+	return s.table.Lookup(tableName, key)
+}
+
+// serve accepts connections on the listener and handles each of them in a new goroutine.
+func serve(l net.Listener, handle func(net.Conn)) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+			handle(conn)
+		}()
+	}
+}
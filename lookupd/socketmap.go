@@ -0,0 +1,114 @@
+package lookupd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/directorz/mailfull-go"
+)
+
+// maxNetstringLength is the maximum length of a netstring that Postfix sends.
+const maxNetstringLength = 100000
+
+// maxNetstringLengthDigits is the maximum number of digits of the length of a netstring.
+var maxNetstringLengthDigits = len(strconv.Itoa(maxNetstringLength))
+
+// Errors for the socketmap protocol.
+var (
+	ErrInvalidNetstring = errors.New("socketmap: invalid netstring")
+)
+
+// ServeSocketmap answers queries of the Postfix socketmap protocol on the listener.
+// The name of each request is the table name. e.g. "domains", "destinations"
+func (s *Server) ServeSocketmap(l net.Listener) error {
+	return serve(l, s.handleSocketmap)
+}
+
+// handleSocketmap handles requests on the connection until it is closed.
+func (s *Server) handleSocketmap(conn net.Conn) {
+	r := bufio.NewReader(conn)
+
+	for {
+		req, err := readNetstring(r)
+		if err != nil {
+			if err != io.EOF {
+				writeNetstring(conn, "PERM "+err.Error())
+			}
+			return
+		}
+
+		if err := writeNetstring(conn, s.socketmapReply(req)); err != nil {
+			return
+		}
+	}
+}
+
+// socketmapReply returns a reply of the request "name key".
+func (s *Server) socketmapReply(req string) string {
+	words := strings.SplitN(req, " ", 2)
+	if len(words) != 2 {
+		return "PERM invalid request"
+	}
+
+	value, found, err := s.lookup(words[0], words[1])
+	if err != nil {
+		if err == mailfull.ErrLookupTableNotExist {
+			return "PERM " + err.Error()
+		}
+		return "TEMP " + err.Error()
+	}
+	if !found {
+		return "NOTFOUND "
+	}
+
+	return "OK " + value
+}
+
+// readNetstring reads a netstring "length:data,".
+func readNetstring(r *bufio.Reader) (string, error) {
+	// the length is read byte by byte not to buffer unlimited data before ":"
+	length, digits := 0, 0
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF && digits > 0 {
+				return "", io.ErrUnexpectedEOF
+			}
+			return "", err
+		}
+
+		if b == ':' && digits > 0 {
+			break
+		}
+		if b < '0' || b > '9' || digits >= maxNetstringLengthDigits {
+			return "", ErrInvalidNetstring
+		}
+
+		length = length*10 + int(b-'0')
+		digits++
+	}
+	if length > maxNetstringLength {
+		return "", ErrInvalidNetstring
+	}
+
+	buf := make([]byte, length+1)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	if buf[length] != ',' {
+		return "", ErrInvalidNetstring
+	}
+
+	return string(buf[:length]), nil
+}
+
+// writeNetstring writes the data as a netstring.
+func writeNetstring(w io.Writer, data string) error {
+	_, err := fmt.Fprintf(w, "%d:%s,", len(data), data)
+	return err
+}
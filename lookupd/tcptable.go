@@ -0,0 +1,66 @@
+package lookupd
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ServeTCPTable answers queries of the Postfix tcp_table protocol on the listener.
+// The protocol has no table name, so a listener serves the table of the input name.
+func (s *Server) ServeTCPTable(l net.Listener, tableName string) error {
+	return serve(l, func(conn net.Conn) {
+		s.handleTCPTable(conn, tableName)
+	})
+}
+
+// handleTCPTable handles requests on the connection until it is closed.
+func (s *Server) handleTCPTable(conn net.Conn, tableName string) {
+	scanner := bufio.NewScanner(conn)
+
+	for scanner.Scan() {
+		if _, err := fmt.Fprintf(conn, "%s\n", s.tcpTableReply(tableName, scanner.Text())); err != nil {
+			return
+		}
+	}
+}
+
+// tcpTableReply returns a reply of the request "get key".
+func (s *Server) tcpTableReply(tableName, req string) string {
+	if !strings.HasPrefix(req, "get ") {
+		return "500 unsupported request"
+	}
+
+	key, err := url.PathUnescape(strings.TrimPrefix(req, "get "))
+	if err != nil {
+		return "500 invalid key"
+	}
+
+	value, found, err := s.lookup(tableName, key)
+	if err != nil {
+		return "400 " + tcpTableEscape(err.Error())
+	}
+	if !found {
+		return "500 not found"
+	}
+
+	return "200 " + tcpTableEscape(value)
+}
+
+// tcpTableEscape encodes whitespace, control characters and "%" as "%XX".
+func tcpTableEscape(str string) string {
+	buf := make([]byte, 0, len(str))
+
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if c <= ' ' || c == '%' || c == 0x7f {
+			buf = append(buf, fmt.Sprintf("%%%02X", c)...)
+			continue
+		}
+		buf = append(buf, c)
+	}
+
+	return string(buf)
+}
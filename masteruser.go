@@ -0,0 +1,204 @@
+package mailfull
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/jsimonetti/pwscheme/ssha"
+)
+
+// MasterUserSeparator separates the login of a User and the name of a MasterUser.
+// e.g. "user@example.com*support"
+// User names cannot contain it.
+const MasterUserSeparator = "*"
+
+// masterUserServices is the list of the services of Dovecot which MasterUsers can log in to.
+// MasterUsers are for mail access, and cannot send mails as other Users by SMTP-AUTH.
+var masterUserServices = []string{ProtocolIMAP, ProtocolPOP3}
+
+// Errors for the MasterUser.
+var (
+	ErrMasterUserNotExist     = errors.New("MasterUser: not exist")
+	ErrMasterUserAlreadyExist = errors.New("MasterUser: already exist")
+
+	ErrInvalidMasterUserName    = errors.New("MasterUser: name incorrect format")
+	ErrInvalidFormatMasterUsers = errors.New("MasterUser: file invalid format")
+)
+
+// MasterUser represents a MasterUser who can log in as any User with its own password.
+type MasterUser struct {
+	name           string
+	hashedPassword string
+}
+
+// NewMasterUser creates a new MasterUser instance.
+func NewMasterUser(name, hashedPassword string) (*MasterUser, error) {
+	m := &MasterUser{}
+
+	if err := m.setName(name); err != nil {
+		return nil, err
+	}
+
+	m.SetHashedPassword(hashedPassword)
+
+	return m, nil
+}
+
+// setName sets the name.
+func (m *MasterUser) setName(name string) error {
+	if !regexp.MustCompile(`^[A-Za-z0-9_\-\.]+$`).MatchString(name) {
+		return ErrInvalidMasterUserName
+	}
+
+	m.name = name
+
+	return nil
+}
+
+// Name returns name.
+func (m *MasterUser) Name() string {
+	return m.name
+}
+
+// SetHashedPassword sets the hashed password.
+func (m *MasterUser) SetHashedPassword(hashedPassword string) {
+	m.hashedPassword = hashedPassword
+}
+
+// HashedPassword returns hashedPassword.
+func (m *MasterUser) HashedPassword() string {
+	return m.hashedPassword
+}
+
+// ValidatePassword returns true if the input matches the hashed password.
+func (m *MasterUser) ValidatePassword(rawPassword string) bool {
+	ok, _ := ssha.Validate(rawPassword, m.hashedPassword)
+
+	return ok
+}
+
+// MasterUsers returns a MasterUser slice.
+func (r *Repository) MasterUsers() ([]*MasterUser, error) {
+	file, err := os.Open(filepath.Join(r.DirConfigPath(), FileNameMasterUsers))
+	if err != nil {
+		if err.(*os.PathError).Err == syscall.ENOENT {
+			return []*MasterUser{}, nil
+		}
+
+		return nil, err
+	}
+	defer file.Close()
+
+	masterUsers := make([]*MasterUser, 0, 10)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		words := strings.Split(scanner.Text(), ":")
+		if len(words) != 2 {
+			return nil, ErrInvalidFormatMasterUsers
+		}
+
+		masterUser, err := NewMasterUser(words[0], words[1])
+		if err != nil {
+			return nil, err
+		}
+
+		masterUsers = append(masterUsers, masterUser)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return masterUsers, nil
+}
+
+// MasterUser returns a MasterUser of the input name.
+func (r *Repository) MasterUser(masterUserName string) (*MasterUser, error) {
+	masterUsers, err := r.MasterUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, masterUser := range masterUsers {
+		if masterUser.Name() == masterUserName {
+			return masterUser, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// MasterUserCreate creates the input MasterUser.
+func (r *Repository) MasterUserCreate(masterUser *MasterUser) error {
+	masterUsers, err := r.MasterUsers()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range masterUsers {
+		if m.Name() == masterUser.Name() {
+			return ErrMasterUserAlreadyExist
+		}
+	}
+
+	masterUsers = append(masterUsers, masterUser)
+
+	if err := r.writeMasterUsersFile(masterUsers); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MasterUserRemove removes a MasterUser of the input name.
+func (r *Repository) MasterUserRemove(masterUserName string) error {
+	masterUsers, err := r.MasterUsers()
+	if err != nil {
+		return err
+	}
+
+	idx := -1
+	for i, masterUser := range masterUsers {
+		if masterUser.Name() == masterUserName {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return ErrMasterUserNotExist
+	}
+
+	masterUsers = append(masterUsers[:idx], masterUsers[idx+1:]...)
+
+	if err := r.writeMasterUsersFile(masterUsers); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeMasterUsersFile writes a MasterUser slice to the file.
+func (r *Repository) writeMasterUsersFile(masterUsers []*MasterUser) error {
+	file, err := os.OpenFile(filepath.Join(r.DirConfigPath(), FileNameMasterUsers), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	sort.Slice(masterUsers, func(i, j int) bool { return masterUsers[i].Name() < masterUsers[j].Name() })
+
+	for _, masterUser := range masterUsers {
+		if _, err := fmt.Fprintf(file, "%s:%s\n", masterUser.Name(), masterUser.HashedPassword()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package mailfull
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// Keys of the Metadata which are used commonly.
+// The created and modified times are set by the Repository.
+const (
+	MetadataDisplayName = "display_name"
+	MetadataPhone       = "phone"
+	MetadataNotes       = "notes"
+	MetadataCreatedAt   = "created_at"
+	MetadataModifiedAt  = "modified_at"
+)
+
+// Errors for the Metadata.
+var (
+	ErrInvalidMetadataKey    = errors.New("Metadata: key incorrect format")
+	ErrInvalidMetadataValue  = errors.New("Metadata: value incorrect format")
+	ErrInvalidFormatMetadata = errors.New("Metadata: file invalid format")
+)
+
+// Metadata represents extensible key-value pairs of a User or a Domain.
+type Metadata map[string]string
+
+// Set sets the value of the key. An empty value removes the key.
+func (m Metadata) Set(key, value string) error {
+	if !regexp.MustCompile(`^[A-Za-z0-9_\-\.]+$`).MatchString(key) {
+		return ErrInvalidMetadataKey
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return ErrInvalidMetadataValue
+	}
+
+	if value == "" {
+		delete(m, key)
+		return nil
+	}
+
+	m[key] = value
+
+	return nil
+}
+
+// Get returns the value of the key. It returns "" if the key is not set.
+func (m Metadata) Get(key string) string {
+	return m[key]
+}
+
+// Keys returns the sorted keys.
+func (m Metadata) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// readMetadataFile returns the Metadata written in the file.
+// It returns empty Metadata if the file does not exist.
+func readMetadataFile(path string) (Metadata, error) {
+	m := Metadata{}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if err.(*os.PathError).Err == syscall.ENOENT {
+			return m, nil
+		}
+
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+
+		// key=value
+		words := strings.SplitN(scanner.Text(), "=", 2)
+		if len(words) != 2 {
+			return nil, ErrInvalidFormatMetadata
+		}
+
+		if err := m.Set(words[0], words[1]); err != nil {
+			return nil, ErrInvalidFormatMetadata
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// writeMetadataFile writes the Metadata to the file.
+// The file is removed if the Metadata is empty.
+func (r *Repository) writeMetadataFile(path string, m Metadata) error {
+	if len(m) == 0 {
+		if err := os.Remove(path); err != nil {
+			if err.(*os.PathError).Err == syscall.ENOENT {
+				return nil
+			}
+
+			return err
+		}
+
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := file.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for _, key := range m.Keys() {
+		if _, err := fmt.Fprintf(file, "%s=%s\n", key, m[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package mailfull
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jsimonetti/pwscheme/ssha"
+)
+
+// Errors for the password.
+var (
+	ErrPasswordMismatch  = errors.New("User: password incorrect")
+	ErrPasswordTooShort  = errors.New("User: password too short")
+	ErrPasswordUnchanged = errors.New("User: password unchanged")
+)
+
+// dummyHashedPassword is compared when the User does not exist,
+// so that the response time does not tell whether the User exists.
+var dummyHashedPassword, _ = ssha.Generate("mailfull", 4)
+
+// HashPassword returns a hashed password of the input.
+// An empty input is hashed to the NeverMatchHashedPassword.
+func HashPassword(rawPassword string) (string, error) {
+	if rawPassword == "" {
+		return NeverMatchHashedPassword, nil
+	}
+
+	return ssha.Generate(rawPassword, 4)
+}
+
+// ValidatePassword returns true if the input matches the hashed password.
+func (u *User) ValidatePassword(rawPassword string) bool {
+	ok, _ := ssha.Validate(rawPassword, u.HashedPassword())
+	return ok
+}
+
+// CheckPasswordPolicy returns an error if the input does not satisfy the password policy.
+func (r *Repository) CheckPasswordPolicy(rawPassword string) error {
+	if utf8.RuneCountInString(rawPassword) < r.PasswordMinLength {
+		return ErrPasswordTooShort
+	}
+
+	return nil
+}
+
+// ActiveUser returns a User of the input name if the User can log in.
+// It returns nil if the Domain or the User does not exist, or the Domain or the User is disabled or has expired.
+func (r *Repository) ActiveUser(domainName, userName string) (*User, error) {
+	domain, err := r.Domain(domainName)
+	if err != nil {
+		if err == ErrInvalidDomainName {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if domain == nil || domain.Disabled() || domain.Expired(time.Now()) {
+		return nil, nil
+	}
+
+	user, err := r.User(domainName, userName)
+	if err != nil {
+		if err == ErrInvalidUserName {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if user == nil || user.Disabled() || user.Expired(time.Now()) {
+		return nil, nil
+	}
+
+	return user, nil
+}
+
+// UserAuthenticate returns a User of the input name if the password is correct.
+// It returns ErrPasswordMismatch also if the User cannot log in.
+func (r *Repository) UserAuthenticate(domainName, userName, rawPassword string) (*User, error) {
+	user, err := r.ActiveUser(domainName, userName)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		ssha.Validate(rawPassword, dummyHashedPassword)
+		return nil, ErrPasswordMismatch
+	}
+
+	if !user.ValidatePassword(rawPassword) {
+		return nil, ErrPasswordMismatch
+	}
+
+	return user, nil
+}
+
+// UserChangePassword changes the password of the User who knows the current password,
+// and applies it to the password database.
+func (r *Repository) UserChangePassword(domainName, userName, currentRawPassword, newRawPassword string) error {
+	user, err := r.UserAuthenticate(domainName, userName, currentRawPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := r.CheckPasswordPolicy(newRawPassword); err != nil {
+		return err
+	}
+	if newRawPassword == currentRawPassword {
+		return ErrPasswordUnchanged
+	}
+
+	hashedPassword, err := HashPassword(newRawPassword)
+	if err != nil {
+		return err
+	}
+	user.SetHashedPassword(hashedPassword)
+
+	if err := r.UserUpdate(domainName, user); err != nil {
+		return err
+	}
+
+	if err := r.GenerateDatabasePasswords(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -34,10 +34,14 @@ var (
 	ErrAliasUserNotExist     = errors.New("AliasUser: not exist")
 	ErrAliasUserAlreadyExist = errors.New("AliasUser: already exist")
 
-	ErrInvalidFormatDomainDisabled = errors.New("Domain: disabled file invalid format")
-	ErrInvalidFormatUsersPassword  = errors.New("User: password file invalid format")
-	ErrInvalidFormatAliasDomain    = errors.New("AliasDomain: file invalid format")
-	ErrInvalidFormatAliasUsers     = errors.New("AliasUsers: file invalid format")
+	ErrInvalidFormatDomainDisabled   = errors.New("Domain: disabled file invalid format")
+	ErrInvalidFormatDomainExpiry     = errors.New("Domain: expiry file invalid format")
+	ErrInvalidFormatUsersPassword    = errors.New("User: password file invalid format")
+	ErrInvalidFormatUserDisabled     = errors.New("User: disabled file invalid format")
+	ErrInvalidFormatUserExpiry       = errors.New("User: expiry file invalid format")
+	ErrInvalidFormatUserRestrictions = errors.New("User: restrictions file invalid format")
+	ErrInvalidFormatAliasDomain      = errors.New("AliasDomain: file invalid format")
+	ErrInvalidFormatAliasUsers       = errors.New("AliasUsers: file invalid format")
 )
 
 // RepositoryConfig is used to configure a Repository.
@@ -47,10 +51,52 @@ type RepositoryConfig struct {
 	Username        string `toml:"username"`
 	CmdPostalias    string `toml:"cmd_postalias"`
 	CmdPostmap      string `toml:"cmd_postmap"`
+
+	PasswordMinLength  int    `toml:"password_min_length"`
+	LookupdSocketmap   string `toml:"lookupd_socketmap"`
+	MailHostname       string `toml:"mail_hostname"`
+	AutoconfigWebRoot  string `toml:"autoconfig_webroot"`
+	RecipientDelimiter string `toml:"recipient_delimiter"`
+	DirDKIMKeysPath    string `toml:"dir_dkim_keys"`
+	DKIMKeyGroup       string `toml:"dkim_key_group"`
+
+	Postfix PostfixConfig `toml:"postfix"`
+	Dovecot DovecotConfig `toml:"dovecot"`
+
+	rootPath string
+}
+
+// PostfixConfig is used to configure the generated Postfix configuration.
+type PostfixConfig struct {
+	MyHostname            string   `toml:"myhostname"`
+	MessageSizeLimit      int      `toml:"message_size_limit"`
+	MailboxSizeLimit      int      `toml:"mailbox_size_limit"`
+	VirtualMailboxLimit   int      `toml:"virtual_mailbox_limit"`
+	TLSCertFile           string   `toml:"tls_cert_file"`
+	TLSKeyFile            string   `toml:"tls_key_file"`
+	TLSCAFile             string   `toml:"tls_ca_file"`
+	RecipientRestrictions []string `toml:"recipient_restrictions"`
+	MUAClientRestrictions []string `toml:"mua_client_restrictions"`
+	SenderRestrictions    []string `toml:"sender_restrictions"`
+}
+
+// DovecotConfig is used to configure the generated Dovecot configuration.
+type DovecotConfig struct {
+	Profile     string   `toml:"profile"`
+	Protocols   []string `toml:"protocols"`
+	TLSCertFile string   `toml:"tls_cert_file"`
+	TLSKeyFile  string   `toml:"tls_key_file"`
+	TLSCAFile   string   `toml:"tls_ca_file"`
+	PAMFallback bool     `toml:"pam_fallback"`
+	LMTP        bool     `toml:"lmtp"`
+	Quota       string   `toml:"quota"`
+	Sieve       bool     `toml:"sieve"`
 }
 
 // Normalize normalizes paramaters of the RepositoryConfig.
 func (c *RepositoryConfig) Normalize(rootPath string) {
+	c.rootPath = rootPath
+
 	if !filepath.IsAbs(c.DirDatabasePath) {
 		c.DirDatabasePath = filepath.Join(rootPath, c.DirDatabasePath)
 	}
@@ -58,6 +104,14 @@ func (c *RepositoryConfig) Normalize(rootPath string) {
 		c.DirMailDataPath = filepath.Join(rootPath, c.DirMailDataPath)
 	}
 
+	if c.AutoconfigWebRoot != "" && !filepath.IsAbs(c.AutoconfigWebRoot) {
+		c.AutoconfigWebRoot = filepath.Join(rootPath, c.AutoconfigWebRoot)
+	}
+
+	if c.DirDKIMKeysPath != "" && !filepath.IsAbs(c.DirDKIMKeysPath) {
+		c.DirDKIMKeysPath = filepath.Join(rootPath, c.DirDKIMKeysPath)
+	}
+
 	if filepath.Base(c.CmdPostalias) != c.CmdPostalias {
 		if !filepath.IsAbs(c.CmdPostalias) {
 			c.CmdPostalias = filepath.Join(rootPath, c.CmdPostalias)
@@ -71,6 +125,11 @@ func (c *RepositoryConfig) Normalize(rootPath string) {
 	}
 }
 
+// DirConfigPath returns the path of the config directory.
+func (c *RepositoryConfig) DirConfigPath() string {
+	return filepath.Join(c.rootPath, DirNameConfig)
+}
+
 // DefaultRepositoryConfig returns a RepositoryConfig with default parameter.
 func DefaultRepositoryConfig() *RepositoryConfig {
 	c := &RepositoryConfig{
@@ -79,6 +138,47 @@ func DefaultRepositoryConfig() *RepositoryConfig {
 		Username:        "",
 		CmdPostalias:    "postalias",
 		CmdPostmap:      "postmap",
+
+		PasswordMinLength:  8,
+		LookupdSocketmap:   "",
+		MailHostname:       "",
+		AutoconfigWebRoot:  "",
+		RecipientDelimiter: "-",
+		DirDKIMKeysPath:    "",
+		DKIMKeyGroup:       "",
+
+		Postfix: PostfixConfig{
+			MyHostname:          "",
+			MessageSizeLimit:    10240000,
+			MailboxSizeLimit:    51200000,
+			VirtualMailboxLimit: 51200000,
+			TLSCertFile:         "/etc/pki/dovecot/certs/dovecot.pem",
+			TLSKeyFile:          "/etc/pki/dovecot/private/dovecot.pem",
+			TLSCAFile:           "",
+			RecipientRestrictions: []string{
+				"permit_mynetworks",
+				"permit_sasl_authenticated",
+				"reject_unauth_destination",
+			},
+			MUAClientRestrictions: []string{
+				"permit_sasl_authenticated",
+				"reject",
+			},
+			SenderRestrictions: []string{
+				"reject_sender_login_mismatch",
+			},
+		},
+		Dovecot: DovecotConfig{
+			Profile:     DovecotProfileLegacy,
+			Protocols:   []string{"imap", "pop3"},
+			TLSCertFile: "/etc/pki/dovecot/certs/dovecot.pem",
+			TLSKeyFile:  "/etc/pki/dovecot/private/dovecot.pem",
+			TLSCAFile:   "",
+			PAMFallback: true,
+			LMTP:        true,
+			Quota:       "",
+			Sieve:       false,
+		},
 	}
 
 	return c
@@ -90,6 +190,8 @@ type Repository struct {
 
 	uid int
 	gid int
+
+	dkimGID int
 }
 
 // NewRepository creates a new Repository instance.
@@ -107,16 +209,40 @@ func NewRepository(c *RepositoryConfig) (*Repository, error) {
 		return nil, err
 	}
 
+	dkimGID := gid
+	if c.DKIMKeyGroup != "" {
+		g, err := user.LookupGroup(c.DKIMKeyGroup)
+		if err != nil {
+			return nil, err
+		}
+		dkimGID, err = strconv.Atoi(g.Gid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	r := &Repository{
 		RepositoryConfig: c,
 
 		uid: uid,
 		gid: gid,
+
+		dkimGID: dkimGID,
 	}
 
 	return r, nil
 }
 
+// UID returns the uid of the owner of database files and maildata files.
+func (r *Repository) UID() int {
+	return r.uid
+}
+
+// GID returns the gid of the owner of database files and maildata files.
+func (r *Repository) GID() int {
+	return r.gid
+}
+
 // OpenRepository opens a Repository and creates a new Repository instance.
 func OpenRepository(basePath string) (*Repository, error) {
 	rootPath, err := filepath.Abs(basePath)
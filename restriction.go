@@ -0,0 +1,241 @@
+package mailfull
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// Protocols which can be restricted for each User.
+// They are the service names of Dovecot.
+const (
+	ProtocolIMAP       = "imap"
+	ProtocolPOP3       = "pop3"
+	ProtocolSMTP       = "smtp"
+	ProtocolSubmission = "submission"
+)
+
+// protocols is the list of the protocols which can be restricted.
+var protocols = []string{ProtocolIMAP, ProtocolPOP3, ProtocolSMTP, ProtocolSubmission}
+
+// Service names which Dovecot passes to passdb lookups, in addition to the protocols.
+// A deny list is generated for each service, because a lookup fails if the passwd-file does not exist.
+// Restricted Users cannot log in to the login services, as auth-helper denies them.
+// The internal services do not log in Users, and are never denied.
+var (
+	loginServices    = []string{"sieve"}
+	internalServices = []string{"lmtp", "doveadm"}
+)
+
+// Errors for the restrictions.
+var (
+	ErrInvalidUserAllowNet = errors.New("User: allowed network incorrect format")
+	ErrInvalidUserProtocol = errors.New("User: protocol unknown")
+)
+
+// Keys of the restrictions file.
+const (
+	restrictionKeyAllowNets = "allow_nets"
+	restrictionKeyProtocols = "protocols"
+)
+
+// SetAllowNets sets the networks from which the User can log in.
+// Each network is an IP address or a CIDR. Empty means anywhere.
+func (u *User) SetAllowNets(allowNets []string) error {
+	for _, allowNet := range allowNets {
+		if !validAllowNet(allowNet) {
+			return ErrInvalidUserAllowNet
+		}
+	}
+
+	u.allowNets = allowNets
+
+	return nil
+}
+
+// AllowNets returns the networks from which the User can log in.
+func (u *User) AllowNets() []string {
+	return u.allowNets
+}
+
+// SetProtocols sets the protocols which the User can use. Empty means all protocols.
+func (u *User) SetProtocols(allowedProtocols []string) error {
+	for _, protocol := range allowedProtocols {
+		if !hasString(protocols, protocol) {
+			return ErrInvalidUserProtocol
+		}
+	}
+
+	u.protocols = allowedProtocols
+
+	return nil
+}
+
+// Protocols returns the protocols which the User can use.
+func (u *User) Protocols() []string {
+	return u.protocols
+}
+
+// AllowsProtocol returns true if the User can use the input protocol.
+func (u *User) AllowsProtocol(protocol string) bool {
+	return len(u.protocols) == 0 || hasString(u.protocols, protocol)
+}
+
+// AllowsIP returns true if the User can log in from the input IP address.
+func (u *User) AllowsIP(ip net.IP) bool {
+	if len(u.allowNets) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+
+	for _, allowNet := range u.allowNets {
+		if _, ipNet, err := net.ParseCIDR(allowNet); err == nil {
+			if ipNet.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		if net.ParseIP(allowNet).Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validAllowNet returns true if the input is an IP address or a CIDR.
+func validAllowNet(allowNet string) bool {
+	if _, _, err := net.ParseCIDR(allowNet); err == nil {
+		return true
+	}
+
+	return net.ParseIP(allowNet) != nil
+}
+
+// userRestrictions returns the allowed networks and the allowed protocols of the input User.
+func (r *Repository) userRestrictions(domainName, userName string) ([]string, []string, error) {
+	if !validDomainName(domainName) {
+		return nil, nil, ErrInvalidDomainName
+	}
+	if !validUserName(userName) {
+		return nil, nil, ErrInvalidUserName
+	}
+
+	file, err := os.Open(filepath.Join(r.DirMailDataPath, domainName, userName, FileNameUserRestrictions))
+	if err != nil {
+		if err.(*os.PathError).Err == syscall.ENOENT {
+			return nil, nil, nil
+		}
+
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var allowNets, allowedProtocols []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		words := strings.SplitN(line, "=", 2)
+		if len(words) != 2 {
+			return nil, nil, ErrInvalidFormatUserRestrictions
+		}
+
+		values := []string{}
+		for _, value := range strings.Split(words[1], ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+
+		switch words[0] {
+		case restrictionKeyAllowNets:
+			allowNets = values
+		case restrictionKeyProtocols:
+			allowedProtocols = values
+		default:
+			return nil, nil, ErrInvalidFormatUserRestrictions
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return allowNets, allowedProtocols, nil
+}
+
+// loadUserRestrictions sets the restrictions read from the User's restrictions file to the input User.
+func (r *Repository) loadUserRestrictions(domainName string, user *User) error {
+	allowNets, allowedProtocols, err := r.userRestrictions(domainName, user.Name())
+	if err != nil {
+		return err
+	}
+
+	if err := user.SetAllowNets(allowNets); err != nil {
+		return ErrInvalidFormatUserRestrictions
+	}
+	if err := user.SetProtocols(allowedProtocols); err != nil {
+		return ErrInvalidFormatUserRestrictions
+	}
+
+	return nil
+}
+
+// writeUserRestrictionsFile writes the restrictions to the User's restrictions file.
+// The file is removed if the User has no restrictions.
+func (r *Repository) writeUserRestrictionsFile(domainName, userName string, allowNets, allowedProtocols []string) error {
+	if !validDomainName(domainName) {
+		return ErrInvalidDomainName
+	}
+	if !validUserName(userName) {
+		return ErrInvalidUserName
+	}
+
+	restrictionsFileName := filepath.Join(r.DirMailDataPath, domainName, userName, FileNameUserRestrictions)
+
+	if len(allowNets) == 0 && len(allowedProtocols) == 0 {
+		if err := os.Remove(restrictionsFileName); err != nil {
+			if err.(*os.PathError).Err == syscall.ENOENT {
+				return nil
+			}
+
+			return err
+		}
+
+		return nil
+	}
+
+	file, err := os.OpenFile(restrictionsFileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := file.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if len(allowNets) > 0 {
+		if _, err := fmt.Fprintf(file, "%s=%s\n", restrictionKeyAllowNets, strings.Join(allowNets, ",")); err != nil {
+			return err
+		}
+	}
+	if len(allowedProtocols) > 0 {
+		if _, err := fmt.Fprintf(file, "%s=%s\n", restrictionKeyProtocols, strings.Join(allowedProtocols, ",")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
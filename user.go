@@ -12,15 +12,34 @@ import (
 	"time"
 )
 
+// userDisabledReject is the content of the disabled file of the User which rejects mail.
+const userDisabledReject = "reject"
+
 // User represents a User.
 type User struct {
 	name           string
 	hashedPassword string
 	forwards       []string
+	disabled       bool
+	rejectsMail    bool
+	expiresAt      time.Time
+	allowNets      []string
+	protocols      []string
+	metadata       Metadata
 }
 
 // NewUser creates a new User instance.
 func NewUser(name, hashedPassword string, forwards []string) (*User, error) {
+	if err := validationError(ErrInvalidUserName, userNameRule(name)); err != nil {
+		return nil, err
+	}
+
+	return newUser(name, hashedPassword, forwards)
+}
+
+// newUser creates a new User instance of an existing User.
+// The name written before the rules of names is accepted as is.
+func newUser(name, hashedPassword string, forwards []string) (*User, error) {
 	u := &User{}
 
 	if err := u.setName(name); err != nil {
@@ -29,14 +48,15 @@ func NewUser(name, hashedPassword string, forwards []string) (*User, error) {
 
 	u.SetHashedPassword(hashedPassword)
 	u.SetForwards(forwards)
+	u.SetMetadata(Metadata{})
 
 	return u, nil
 }
 
 // setName sets the name.
 func (u *User) setName(name string) error {
-	if !validUserName(name) {
-		return ErrInvalidUserName
+	if err := validationError(ErrInvalidUserName, existingNameRule(name)); err != nil {
+		return err
 	}
 
 	u.name = name
@@ -69,6 +89,52 @@ func (u *User) Forwards() []string {
 	return u.forwards
 }
 
+// SetDisabled disables the User if the input is true.
+// A disabled User cannot log in, but still receives mail unless it rejects mail.
+func (u *User) SetDisabled(disabled bool) {
+	u.disabled = disabled
+}
+
+// Disabled returns true if the User is disabled.
+func (u *User) Disabled() bool {
+	return u.disabled
+}
+
+// SetRejectsMail makes the disabled User reject incoming mail if the input is true.
+func (u *User) SetRejectsMail(rejectsMail bool) {
+	u.rejectsMail = rejectsMail
+}
+
+// RejectsMail returns true if the User is disabled and rejects incoming mail.
+func (u *User) RejectsMail() bool {
+	return u.disabled && u.rejectsMail
+}
+
+// SetExpiresAt sets the time when the User expires. The zero time means the User never expires.
+func (u *User) SetExpiresAt(expiresAt time.Time) {
+	u.expiresAt = expiresAt
+}
+
+// ExpiresAt returns the time when the User expires.
+func (u *User) ExpiresAt() time.Time {
+	return u.expiresAt
+}
+
+// Expired returns true if the User has expired at the input time.
+func (u *User) Expired(now time.Time) bool {
+	return expired(u.expiresAt, now)
+}
+
+// SetMetadata sets the Metadata.
+func (u *User) SetMetadata(metadata Metadata) {
+	u.metadata = metadata
+}
+
+// Metadata returns the Metadata. It can be changed by Metadata.Set.
+func (u *User) Metadata() Metadata {
+	return u.metadata
+}
+
 // Users returns a User slice.
 func (r *Repository) Users(domainName string) ([]*User, error) {
 	domain, err := r.Domain(domainName)
@@ -111,11 +177,34 @@ func (r *Repository) Users(domainName string) ([]*User, error) {
 			hashedPassword = ""
 		}
 
-		user, err := NewUser(name, hashedPassword, forwards)
+		user, err := newUser(name, hashedPassword, forwards)
 		if err != nil {
 			continue
 		}
 
+		disabled, rejectsMail, err := r.userDisabled(domainName, name)
+		if err != nil {
+			return nil, err
+		}
+		user.SetDisabled(disabled)
+		user.SetRejectsMail(rejectsMail)
+
+		expiresAt, err := readExpiryFile(filepath.Join(r.DirMailDataPath, domainName, name, FileNameUserExpiry), ErrInvalidFormatUserExpiry)
+		if err != nil {
+			return nil, err
+		}
+		user.SetExpiresAt(expiresAt)
+
+		if err := r.loadUserRestrictions(domainName, user); err != nil {
+			return nil, err
+		}
+
+		metadata, err := readMetadataFile(filepath.Join(r.DirMailDataPath, domainName, name, FileNameUserMetadata))
+		if err != nil {
+			return nil, err
+		}
+		user.SetMetadata(metadata)
+
 		users = append(users, user)
 	}
 
@@ -166,14 +255,69 @@ func (r *Repository) User(domainName, userName string) (*User, error) {
 		hashedPassword = ""
 	}
 
-	user, err := NewUser(name, hashedPassword, forwards)
+	user, err := newUser(name, hashedPassword, forwards)
 	if err != nil {
 		return nil, err
 	}
 
+	disabled, rejectsMail, err := r.userDisabled(domainName, name)
+	if err != nil {
+		return nil, err
+	}
+	user.SetDisabled(disabled)
+	user.SetRejectsMail(rejectsMail)
+
+	expiresAt, err := readExpiryFile(filepath.Join(r.DirMailDataPath, domainName, name, FileNameUserExpiry), ErrInvalidFormatUserExpiry)
+	if err != nil {
+		return nil, err
+	}
+	user.SetExpiresAt(expiresAt)
+
+	if err := r.loadUserRestrictions(domainName, user); err != nil {
+		return nil, err
+	}
+
+	metadata, err := readMetadataFile(filepath.Join(r.DirMailDataPath, domainName, name, FileNameUserMetadata))
+	if err != nil {
+		return nil, err
+	}
+	user.SetMetadata(metadata)
+
 	return user, nil
 }
 
+// userDisabled returns whether the input User is disabled and rejects incoming mail.
+// The disabled file is empty, or contains "reject" if the User rejects mail.
+func (r *Repository) userDisabled(domainName, userName string) (bool, bool, error) {
+	if !validDomainName(domainName) {
+		return false, false, ErrInvalidDomainName
+	}
+	if !validUserName(userName) {
+		return false, false, ErrInvalidUserName
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(r.DirMailDataPath, domainName, userName, FileNameUserDisable))
+	if err != nil {
+		if pathErr, ok := err.(*os.PathError); ok && pathErr.Err == syscall.ENOENT {
+			return false, false, nil
+		}
+		if pathErr, ok := err.(*os.PathError); ok && pathErr.Err == syscall.EISDIR {
+			return false, false, ErrInvalidFormatUserDisabled
+		}
+
+		return false, false, err
+	}
+
+	switch strings.TrimSpace(string(data)) {
+	case "":
+		return true, false, nil
+	case userDisabledReject:
+		return true, true, nil
+	default:
+		return false, false, ErrInvalidFormatUserDisabled
+	}
+}
+
 // usersHashedPassword returns a string map of usernames to the hashed password.
 func (r *Repository) usersHashedPassword(domainName string) (map[string]string, error) {
 	if !validDomainName(domainName) {
@@ -257,6 +401,9 @@ func (r *Repository) UserCreate(domainName string, user *User) error {
 	if existAliasUser != nil {
 		return ErrAliasUserAlreadyExist
 	}
+	if err := r.checkNameCollision(domainName, user.Name()); err != nil {
+		return err
+	}
 
 	userDirPath := filepath.Join(r.DirMailDataPath, domainName, user.Name())
 
@@ -276,6 +423,8 @@ func (r *Repository) UserCreate(domainName string, user *User) error {
 		}
 	}
 
+	user.Metadata()[MetadataCreatedAt] = time.Now().Format(time.RFC3339)
+
 	if err := r.UserUpdate(domainName, user); err != nil {
 		return err
 	}
@@ -306,6 +455,23 @@ func (r *Repository) UserUpdate(domainName string, user *User) error {
 		return err
 	}
 
+	if err := r.writeUserDisabledFile(domainName, user.Name(), user.Disabled(), user.RejectsMail()); err != nil {
+		return err
+	}
+
+	if err := r.writeExpiryFile(filepath.Join(r.DirMailDataPath, domainName, user.Name(), FileNameUserExpiry), user.ExpiresAt()); err != nil {
+		return err
+	}
+
+	if err := r.writeUserRestrictionsFile(domainName, user.Name(), user.AllowNets(), user.Protocols()); err != nil {
+		return err
+	}
+
+	user.Metadata()[MetadataModifiedAt] = time.Now().Format(time.RFC3339)
+	if err := r.writeMetadataFile(filepath.Join(r.DirMailDataPath, domainName, user.Name(), FileNameUserMetadata), user.Metadata()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -399,3 +565,44 @@ func (r *Repository) writeUserForwardsFile(domainName, userName string, forwards
 
 	return nil
 }
+
+// writeUserDisabledFile creates/removes the disabled file of the User.
+func (r *Repository) writeUserDisabledFile(domainName, userName string, disabled, rejectsMail bool) error {
+	if !validDomainName(domainName) {
+		return ErrInvalidDomainName
+	}
+	if !validUserName(userName) {
+		return ErrInvalidUserName
+	}
+
+	userDisabledFileName := filepath.Join(r.DirMailDataPath, domainName, userName, FileNameUserDisable)
+
+	if !disabled {
+		if err := os.Remove(userDisabledFileName); err != nil {
+			if err.(*os.PathError).Err == syscall.ENOENT {
+				return nil
+			}
+
+			return err
+		}
+
+		return nil
+	}
+
+	file, err := os.OpenFile(userDisabledFileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := file.Chown(r.uid, r.gid); err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if rejectsMail {
+		if _, err := fmt.Fprintf(file, "%s\n", userDisabledReject); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package mailfull
 import (
 	"errors"
 	"regexp"
+	"strings"
+	"unicode/utf8"
 )
 
 // Errors for incorrect format.
@@ -13,40 +15,203 @@ var (
 	ErrInvalidUserName          = errors.New("User: name incorrect format")
 	ErrInvalidAliasUserName     = errors.New("AliasUser: name incorrect format")
 	ErrInvalidAliasUserTarget   = errors.New("AliasUser: target incorrect format")
+	ErrInvalidAliasUserSender   = errors.New("AliasUser: sender incorrect format")
 	ErrInvalidCatchAllUserName  = errors.New("CatchAllUser: name incorrect format")
 )
 
-// validDomainName returns true if the input is correct format.
+// Rules of the format which are violated.
+var (
+	ErrRuleEmpty               = errors.New("empty")
+	ErrRuleLocalPartTooLong    = errors.New("local part longer than 64 octets")
+	ErrRuleLocalPartCharacter  = errors.New("local part contains a character not allowed")
+	ErrRuleLocalPartDot        = errors.New("local part begins or ends with a dot, or contains consecutive dots")
+	ErrRuleSeparator           = errors.New(`contains ":" or "," used as separators in the files`)
+	ErrRuleMasterUserSeparator = errors.New(`contains "` + MasterUserSeparator + `" used as the separator of master user logins`)
+	ErrRuleDomainNameTooLong   = errors.New("domain name longer than 253 octets")
+	ErrRuleLabelEmpty          = errors.New("domain name contains an empty label")
+	ErrRuleLabelTooLong        = errors.New("label longer than 63 octets")
+	ErrRuleLabelCharacter      = errors.New("label contains a character other than letters, digits and hyphens")
+	ErrRuleLabelHyphen         = errors.New("label begins or ends with a hyphen")
+	ErrRuleLabelIDNA           = errors.New("label begins with \"xn--\" but is not a valid IDNA A-label")
+	ErrRuleTopLabelNumeric     = errors.New("top-level label is all-numeric")
+)
+
+// Limits of lengths in octets. (RFC 5321, RFC 1035)
+const (
+	maxLocalPartLength  = 64
+	maxDomainNameLength = 253
+	maxLabelLength      = 63
+)
+
+// Formats of names accepted before domainNameRule and nameRule.
+// Existing entries in these formats are still read, and are reported by Check.
+var (
+	legacyDomainNameRegexp = regexp.MustCompile(`^([A-Za-z0-9\-]+\.)*[A-Za-z]+$`)
+	legacyNameRegexp       = regexp.MustCompile(`^[^\.\s@/][^\s@/]+$`)
+)
+
+// nameSpecials is characters allowed in names in addition to letters and digits.
+// It is atext of RFC 5322 except "/", because names are used as file names.
+const nameSpecials = "!#$%&'*+-=?^_`{|}~"
+
+// ValidationError represents an incorrect format with the rule which the input violates.
+type ValidationError struct {
+	Err  error
+	Rule error
+}
+
+// Error returns the message. e.g. "Domain: name incorrect format (label longer than 63 octets)"
+func (e *ValidationError) Error() string {
+	return e.Err.Error() + " (" + e.Rule.Error() + ")"
+}
+
+// validationError returns a ValidationError of the rule, or nil if the rule is nil.
+func validationError(err, rule error) error {
+	if rule == nil {
+		return nil
+	}
+
+	return &ValidationError{Err: err, Rule: rule}
+}
+
+// domainNameRule returns the rule which the domain name violates, or nil.
+func domainNameRule(name string) error {
+	if name == "" {
+		return ErrRuleEmpty
+	}
+	if len(name) > maxDomainNameLength {
+		return ErrRuleDomainNameTooLong
+	}
+
+	labels := strings.Split(name, ".")
+	for _, label := range labels {
+		if label == "" {
+			return ErrRuleLabelEmpty
+		}
+		if len(label) > maxLabelLength {
+			return ErrRuleLabelTooLong
+		}
+
+		for i := 0; i < len(label); i++ {
+			if !isLetterOrDigit(label[i]) && label[i] != '-' {
+				return ErrRuleLabelCharacter
+			}
+		}
+
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return ErrRuleLabelHyphen
+		}
+
+		if !validALabel(label) {
+			return ErrRuleLabelIDNA
+		}
+	}
+
+	if strings.Trim(labels[len(labels)-1], "0123456789") == "" {
+		return ErrRuleTopLabelNumeric
+	}
+
+	return nil
+}
+
+// nameRule returns the rule which the name used as the local part of addresses violates, or nil.
+// A name is a dot-atom which can be also used as a file name.
+func nameRule(name string) error {
+	if name == "" {
+		return ErrRuleEmpty
+	}
+	if len(name) > maxLocalPartLength {
+		return ErrRuleLocalPartTooLong
+	}
+	if !utf8.ValidString(name) {
+		return ErrRuleLocalPartCharacter
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !isLetterOrDigit(c) && c != '.' && c < utf8.RuneSelf && !strings.ContainsRune(nameSpecials, rune(c)) {
+			return ErrRuleLocalPartCharacter
+		}
+	}
+
+	if !validDots(name) {
+		return ErrRuleLocalPartDot
+	}
+
+	return nil
+}
+
+// userNameRule returns the rule which the name of a User violates, or nil.
+// A User name cannot contain MasterUserSeparator, because the login would be split by Dovecot.
+func userNameRule(name string) error {
+	if err := nameRule(name); err != nil {
+		return err
+	}
+	if strings.Contains(name, MasterUserSeparator) {
+		return ErrRuleMasterUserSeparator
+	}
+
+	return nil
+}
+
+// validDots returns true if the input does not begin or end with a dot, and does not contain consecutive dots.
+func validDots(s string) bool {
+	return !strings.HasPrefix(s, ".") && !strings.HasSuffix(s, ".") && !strings.Contains(s, "..")
+}
+
+// isLetterOrDigit returns true if the input is an ASCII letter or digit.
+func isLetterOrDigit(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9'
+}
+
+// existingDomainNameRule returns the rule which the name of an existing domain violates, or nil.
+// Names accepted before domainNameRule are allowed.
+func existingDomainNameRule(name string) error {
+	rule := domainNameRule(name)
+	if rule != nil && legacyDomainNameRegexp.MatchString(name) {
+		return nil
+	}
+
+	return rule
+}
+
+// existingNameRule returns the rule which the name of an existing entry violates, or nil.
+// Names accepted before nameRule are allowed.
+func existingNameRule(name string) error {
+	rule := nameRule(name)
+	if rule != nil && legacyNameRegexp.MatchString(name) && !strings.ContainsAny(name, ":,") {
+		return nil
+	}
+
+	return rule
+}
+
+// validDomainName returns true if the input is correct format for an existing Domain.
 func validDomainName(name string) bool {
-	return regexp.MustCompile(`^([A-Za-z0-9\-]+\.)*[A-Za-z]+$`).MatchString(name)
+	return existingDomainNameRule(name) == nil
 }
 
-// validAliasDomainName returns true if the input is correct format.
+// validAliasDomainName returns true if the input is correct format for an existing AliasDomain.
 func validAliasDomainName(name string) bool {
-	return regexp.MustCompile(`^([A-Za-z0-9\-]+\.)*[A-Za-z]+$`).MatchString(name)
+	return existingDomainNameRule(name) == nil
 }
 
-// validAliasDomainTarget returns true if the input is correct format.
+// validAliasDomainTarget returns true if the input is correct format for the target of an existing AliasDomain.
 func validAliasDomainTarget(target string) bool {
-	return regexp.MustCompile(`^([A-Za-z0-9\-]+\.)*[A-Za-z]+$`).MatchString(target)
+	return existingDomainNameRule(target) == nil
 }
 
-// validUserName returns true if the input is correct format.
+// validUserName returns true if the input is correct format for an existing User.
 func validUserName(name string) bool {
-	return regexp.MustCompile(`^[^\.\s@][^\s@]+$`).MatchString(name)
+	return existingNameRule(name) == nil
 }
 
-// validAliasUserName returns true if the input is correct format.
+// validAliasUserName returns true if the input is correct format for an existing AliasUser.
 func validAliasUserName(name string) bool {
-	return regexp.MustCompile(`^[^\.\s@][^\s@]+$`).MatchString(name)
-}
-
-// validAliasUserTarget returns true if the input is correct format.
-func validAliasUserTarget(target string) bool {
-	return regexp.MustCompile(`^[^\.\s@][^\s@]+@([A-Za-z0-9\-]+\.)*[A-Za-z]+$`).MatchString(target)
+	return existingNameRule(name) == nil
 }
 
-// validCatchAllUserName returns true if the input is correct format.
+// validCatchAllUserName returns true if the input is correct format for an existing CatchAllUser.
 func validCatchAllUserName(name string) bool {
-	return regexp.MustCompile(`^[^\.\s@][^\s@]+$`).MatchString(name)
+	return existingNameRule(name) == nil
 }